@@ -24,6 +24,8 @@ type Analyzer struct {
 	depends     []string
 	k           *koanf.Koanf
 	strictMode  bool
+	// positions of reported diagnostics
+	reported map[string]struct{}
 
 	doc      *spec.T
 	packages []*packages.Package
@@ -36,6 +38,7 @@ func NewAnalyzer(k *koanf.Koanf) *Analyzer {
 		plugins:     make([]Plugin, 0),
 		definitions: make(Definitions),
 		k:           k,
+		reported:    make(map[string]struct{}),
 	}
 
 	components := spec.NewComponents()
@@ -101,6 +104,7 @@ func (a *Analyzer) Process(packagePath string) *Analyzer {
 
 	for pkgGroupIdx, pkg := range pkgList {
 		LogDebug("Process: 处理第%d个包组，包含%d个包", pkgGroupIdx+1, len(pkg))
+		a.packages = append(a.packages, pkg...)
 		a.definitions = make(Definitions)
 
 		LogDebug("Process: 开始加载定义")
@@ -132,6 +136,12 @@ func (a *Analyzer) Process(packagePath string) *Analyzer {
 
 				LogDebug("Process: 创建上下文并处理文件，包: %s，文件数: %d", pkg.PkgPath, len(pkg.Syntax))
 				ctx := a.context().Block().WithPackage(pkg)
+				comment := a.packageComment(ctx, pkg)
+				if comment.Ignore() {
+					LogDebug("Process: 包已忽略，跳过: %s", pkg.PkgPath)
+					return true
+				}
+				ctx.commentStack.comment = comment
 				for fileIdx, file := range pkg.Syntax {
 					LogDebug("Process: 处理第%d个文件", fileIdx+1)
 					a.processFile(ctx.Block().WithFile(file), file, pkg)
@@ -243,11 +253,27 @@ func (a *Analyzer) load(pkgPath string) [][]*packages.Package {
 	return [][]*packages.Package{packs}
 }
 
+// packageComment parses package doc in doc.go, which declares defaults of all the routes registered in the package
+func (a *Analyzer) packageComment(ctx *Context, pkg *packages.Package) *Comment {
+	for _, file := range pkg.Syntax {
+		if filepath.Base(pkg.Fset.Position(file.Package).Filename) != "doc.go" {
+			continue
+		}
+		comment := ctx.WithFile(file).ParseComment(file.Doc)
+		comment.ReportUnknownAnnotations(ctx, "package "+pkg.Name)
+		declareTags(a.doc, comment)
+		return comment
+	}
+	return nil
+}
+
 func (a *Analyzer) processFile(ctx *Context, file *ast.File, pkg *packages.Package) {
 	comment := ctx.ParseComment(file.Doc)
 	if comment.Ignore() {
 		return
 	}
+	comment.ReportUnknownAnnotations(ctx, "file "+filepath.Base(pkg.Fset.Position(file.Package).Filename))
+	declareTags(a.doc, comment)
 	ctx.commentStack.comment = comment
 
 	ast.Inspect(file, func(node ast.Node) bool {
@@ -270,6 +296,7 @@ func (a *Analyzer) funDecl(ctx *Context, node *ast.FuncDecl, file *ast.File, pkg
 	if comment.Ignore() {
 		return
 	}
+	declareTags(a.doc, comment)
 	ctx.commentStack.comment = comment
 
 	ast.Inspect(node, func(node ast.Node) bool {
@@ -336,16 +363,22 @@ func (a *Analyzer) loadDefinitionsFromPkg(pkg *packages.Package, moduleDir strin
 					LogDebug("loadDefinitionsFromPkg: 找到函数定义: %s", node.Name.Name)
 					a.definitions.Set(NewFuncDefinition(pkg, file, node))
 					return false
-				case *ast.TypeSpec:
-					LogDebug("loadDefinitionsFromPkg: 找到类型定义: %s", node.Name.Name)
-					a.definitions.Set(NewTypeDefinition(pkg, file, node))
-					return false
 				case *ast.GenDecl:
 					if node.Tok == token.CONST {
 						LogDebug("loadDefinitionsFromPkg: 找到常量定义")
 						a.loadEnumDefinition(pkg, file, node)
 						return false
 					}
+					if node.Tok == token.TYPE {
+						for _, item := range node.Specs {
+							typeSpec := item.(*ast.TypeSpec)
+							LogDebug("loadDefinitionsFromPkg: 找到类型定义: %s", typeSpec.Name.Name)
+							def := NewTypeDefinition(pkg, file, typeSpec)
+							def.decl = node
+							a.definitions.Set(def)
+						}
+						return false
+					}
 					return true
 				}
 				return true
@@ -392,7 +425,7 @@ func (a *Analyzer) loadEnumDefinition(pkg *packages.Package, file *ast.File, nod
 			}
 			typeDef := def.(*TypeDefinition)
 			value := ConvertStrToBasicType(c.Val().ExactString(), basicType)
-			enumItem := spec.NewExtendEnumItem(name.Name, value, strings.TrimSpace(valueSpec.Doc.Text()))
+			enumItem := spec.NewExtendEnumItem(name.Name, value, strings.TrimSpace(joinCommentGroups(valueSpec.Doc, valueSpec.Comment).Text()))
 			typeDef.Enums = append(typeDef.Enums, enumItem)
 		}
 	}
@@ -403,6 +436,7 @@ func (a *Analyzer) blockStmt(ctx *Context, node *ast.BlockStmt, file *ast.File,
 	if comment.Ignore() {
 		return
 	}
+	declareTags(a.doc, comment)
 	ctx.commentStack.comment = comment
 
 	a.analyze(ctx, node)
@@ -14,6 +14,30 @@ const (
 	ID
 	Deprecated
 	Security
+	OneOf
+	Discriminator
+	DiscriminatorValue
+	ReadOnly
+	WriteOnly
+	Param
+	Response
+	Request
+	Min
+	Max
+	MinLength
+	MaxLength
+	Pattern
+	Format
+	Enum
+	Example
+	Default
+	Nullable
+	Extension
+	Server
+	Prefix
+	TagDescription
+	Callback
+	Internal
 )
 
 type Annotation interface {
@@ -51,6 +75,8 @@ func (a *ProduceAnnotation) Type() Type {
 type UnresolvedAnnotation struct {
 	Tag    string
 	Tokens []*Token
+	// column of Tag in the comment line
+	Column int
 }
 
 func (a *UnresolvedAnnotation) Type() Type {
@@ -89,6 +115,9 @@ func (a *IdAnnotation) Type() Type {
 	return ID
 }
 
+// SecurityNone is the name of security scheme which marks an operation as public (empty security requirements)
+const SecurityNone = "none"
+
 type SecurityAnnotation struct {
 	Name   string
 	Params []string
@@ -101,3 +130,172 @@ func newSecurityAnnotation(name string, params []string) *SecurityAnnotation {
 func (a *SecurityAnnotation) Type() Type {
 	return Security
 }
+
+type OneOfAnnotation struct {
+	// Types is the list of candidate types. Each item is either a type
+	// expression (e.g. "pkg.Cat") or "value=pkg.Cat" to specify the
+	// discriminator mapping value explicitly.
+	Types []string
+}
+
+func (a *OneOfAnnotation) Type() Type {
+	return OneOf
+}
+
+type DiscriminatorAnnotation struct {
+	PropertyName string
+}
+
+func (a *DiscriminatorAnnotation) Type() Type {
+	return Discriminator
+}
+
+type DiscriminatorValueAnnotation struct {
+	Value string
+}
+
+func (a *DiscriminatorValueAnnotation) Type() Type {
+	return DiscriminatorValue
+}
+
+type ParamAnnotation struct {
+	Name        string
+	In          string // query | path | header | cookie
+	DataType    string // string | integer | number | boolean | file, or array of them like "[]string"
+	Required    bool
+	Description string
+}
+
+func (a *ParamAnnotation) Type() Type {
+	return Param
+}
+
+type ResponseAnnotation struct {
+	Code        string // status code or "default"
+	DataType    string // type expression. e.g. "pkg.ErrorResp", "pkg.Page[pkg.User]". optional
+	Description string
+	// column of DataType in the comment line
+	TypeColumn int
+}
+
+func (a *ResponseAnnotation) Type() Type {
+	return Response
+}
+
+type RequestAnnotation struct {
+	DataType    string // type expression. e.g. "pkg.CreateReq"
+	ContentType string // optional
+	// column of DataType in the comment line
+	TypeColumn int
+}
+
+func (a *RequestAnnotation) Type() Type {
+	return Request
+}
+
+// LimitAnnotation is @min or @max
+type LimitAnnotation struct {
+	t     Type
+	Value float64
+}
+
+func (a *LimitAnnotation) Type() Type {
+	return a.t
+}
+
+// LengthAnnotation is @minLength or @maxLength
+type LengthAnnotation struct {
+	t     Type
+	Value uint64
+}
+
+func (a *LengthAnnotation) Type() Type {
+	return a.t
+}
+
+type PatternAnnotation struct {
+	Pattern string
+}
+
+func (a *PatternAnnotation) Type() Type {
+	return Pattern
+}
+
+type FormatAnnotation struct {
+	Format string
+}
+
+func (a *FormatAnnotation) Type() Type {
+	return Format
+}
+
+type EnumAnnotation struct {
+	// raw text of values. quoted values are always strings, others are converted according to the type of the field
+	Values []string
+}
+
+func (a *EnumAnnotation) Type() Type {
+	return Enum
+}
+
+// ValueAnnotation is @example or @default
+type ValueAnnotation struct {
+	t Type
+	// raw text of the value. e.g. `"foo"`, `1`, `{"a": 1}`
+	Value string
+}
+
+func (a *ValueAnnotation) Type() Type {
+	return a.t
+}
+
+// ExtensionAnnotation is vendor extension like "@x-rate-limit 100"
+type ExtensionAnnotation struct {
+	Name  string // e.g. "x-rate-limit"
+	Value interface{}
+}
+
+func (a *ExtensionAnnotation) Type() Type {
+	return Extension
+}
+
+type ServerAnnotation struct {
+	URL         string
+	Description string
+}
+
+func (a *ServerAnnotation) Type() Type {
+	return Server
+}
+
+// PrefixAnnotation is path prefix of the routes registered in the file or package
+type PrefixAnnotation struct {
+	Path string
+}
+
+func (a *PrefixAnnotation) Type() Type {
+	return Prefix
+}
+
+type TagDescriptionAnnotation struct {
+	Tag         string
+	Description string
+}
+
+func (a *TagDescriptionAnnotation) Type() Type {
+	return TagDescription
+}
+
+type CallbackAnnotation struct {
+	Name        string
+	Expression  string // runtime expression of callback url. e.g. "{$request.body#/url}"
+	Method      string // upper case http method
+	DataType    string // type expression of request body. optional
+	Description string
+	// column of DataType in the comment line
+	TypeColumn int
+}
+
+func (a *CallbackAnnotation) Type() Type {
+	return Callback
+}
@@ -17,7 +17,7 @@ const (
 )
 
 var patterns = []*pattern{
-	newPattern(tokenTag, "^@[a-zA-Z_]+\\w*"),
+	newPattern(tokenTag, "^@[a-zA-Z_][\\w-]*"),
 	newPattern(tokenString, "^\"(\\\\.|[^\"])*\""),
 	newPattern(tokenNumber, "^[+-]?([0-9]*[.])?[0-9]+"),
 	newPattern(tokenBool, "/^(true|false)/i"),
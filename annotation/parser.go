@@ -1,7 +1,10 @@
 package annotation
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -80,21 +83,65 @@ func (p *Parser) parse() (Annotation, error) {
 		return newSimpleAnnotation(Deprecated), nil
 	case "@security":
 		return p.security()
-	default: // unresolved plugin
+	case "@public":
+		return newSecurityAnnotation(SecurityNone, make([]string, 0)), nil
+	case "@internal":
+		return newSimpleAnnotation(Internal), nil
+	case "@readonly":
+		return newSimpleAnnotation(ReadOnly), nil
+	case "@writeonly":
+		return newSimpleAnnotation(WriteOnly), nil
+	case "@param":
+		return p.param()
+	case "@response":
+		return p.response()
+	case "@request":
+		return p.request()
+	case "@min":
+		return p.limit(Min, tag.Image)
+	case "@max":
+		return p.limit(Max, tag.Image)
+	case "@minlength":
+		return p.length(MinLength, tag.Image)
+	case "@maxlength":
+		return p.length(MaxLength, tag.Image)
+	case "@pattern":
+		return p.pattern()
+	case "@format":
+		return p.format()
+	case "@enum":
+		return p.enum()
+	case "@example":
+		return p.value(Example, tag.Image)
+	case "@default":
+		return p.value(Default, tag.Image)
+	case "@nullable":
+		return newSimpleAnnotation(Nullable), nil
+	case "@callback":
+		return p.callback()
+	case "@tagdescription":
+		return p.tagDescription()
+	case "@server":
+		return p.server()
+	case "@prefix":
+		return p.prefix()
+	case "@oneof":
+		return p.oneOf()
+	case "@discriminator":
+		return p.discriminator()
+	case "@discriminatorvalue":
+		return p.discriminatorValue()
+	default:
+		if strings.HasPrefix(strings.ToLower(tag.Image), "@x-") {
+			return p.extension(tag)
+		}
+		// unresolved plugin
 		return p.unresolved(tag), nil
 	}
 }
 
 func (p *Parser) consume(typ TokenType) (*Token, error) {
-	for {
-		t := p.lookahead()
-		if t != nil && t.Type == tokenWhiteSpace {
-			p.position += 1
-			p.column += len(t.Image)
-		} else {
-			break
-		}
-	}
+	p.skipWhitespace()
 
 	t := p.lookahead()
 	if t == nil {
@@ -109,6 +156,18 @@ func (p *Parser) consume(typ TokenType) (*Token, error) {
 	return t, nil
 }
 
+func (p *Parser) skipWhitespace() {
+	for {
+		t := p.lookahead()
+		if t != nil && t.Type == tokenWhiteSpace {
+			p.position += 1
+			p.column += len(t.Image)
+		} else {
+			break
+		}
+	}
+}
+
 func (p *Parser) consumeAny() *Token {
 	t := p.lookahead()
 	if t == nil {
@@ -120,6 +179,16 @@ func (p *Parser) consumeAny() *Token {
 	return t
 }
 
+// consumeValue skips leading whitespaces and consumes the next non-whitespace token
+func (p *Parser) consumeValue() *Token {
+	for {
+		t := p.consumeAny()
+		if t == nil || t.Type != tokenWhiteSpace {
+			return t
+		}
+	}
+}
+
 func (p *Parser) lookahead() *Token {
 	if !p.hasMore() {
 		return nil
@@ -158,6 +227,7 @@ func (p *Parser) unresolved(tag *Token) Annotation {
 	return &UnresolvedAnnotation{
 		Tag:    tag.Image,
 		Tokens: p.tokens,
+		Column: p.column - len(tag.Image),
 	}
 }
 
@@ -220,3 +290,377 @@ func (p *Parser) security() (*SecurityAnnotation, error) {
 
 	return &security, nil
 }
+
+// @oneOf pkg.Cat pkg.Dog
+// @oneOf cat=pkg.Cat dog=pkg.Dog
+func (p *Parser) oneOf() (*OneOfAnnotation, error) {
+	var res = OneOfAnnotation{Types: make([]string, 0)}
+	for p.hasMore() {
+		token := p.consumeAny()
+		if token.Type == tokenIdentifier {
+			res.Types = append(res.Types, token.Image)
+		}
+	}
+	if len(res.Types) == 0 {
+		return nil, NewParseError(p.column, "expect at least one type after @oneOf")
+	}
+	return &res, nil
+}
+
+// @discriminator propertyName
+func (p *Parser) discriminator() (*DiscriminatorAnnotation, error) {
+	name, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect property name after @discriminator")
+	}
+	return &DiscriminatorAnnotation{PropertyName: name.Image}, nil
+}
+
+// @discriminatorValue value
+func (p *Parser) discriminatorValue() (*DiscriminatorValueAnnotation, error) {
+	token := p.consumeValue()
+	if token == nil {
+		return nil, NewParseError(p.column, "expect value after @discriminatorValue")
+	}
+	return &DiscriminatorValueAnnotation{Value: strings.Trim(token.Image, "\"")}, nil
+}
+
+var paramLocations = []string{"query", "path", "header", "cookie"}
+
+var paramDataTypes = []string{
+	"string", "integer", "int", "int32", "int64", "number", "float", "float32", "float64", "boolean", "bool", "file",
+}
+
+// @param name in type [required] "description"
+func (p *Parser) param() (*ParamAnnotation, error) {
+	name, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect name after @param")
+	}
+	var res = ParamAnnotation{Name: name.Image}
+
+	p.skipWhitespace()
+	column := p.column
+	in, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect location (query|path|header|cookie) after parameter name")
+	}
+	res.In = strings.ToLower(in.Image)
+	if !contains(paramLocations, res.In) {
+		return nil, NewParseError(column, fmt.Sprintf("invalid parameter location '%s'. available: %s", in.Image, strings.Join(paramLocations, ", ")))
+	}
+
+	p.skipWhitespace()
+	column = p.column
+	dataType, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect type after parameter location")
+	}
+	res.DataType = dataType.Image
+	if !contains(paramDataTypes, strings.TrimPrefix(res.DataType, "[]")) {
+		return nil, NewParseError(column, fmt.Sprintf("invalid parameter type '%s'. available: %s (prefix with '[]' for array)", dataType.Image, strings.Join(paramDataTypes, ", ")))
+	}
+
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		switch {
+		case token.Type == tokenIdentifier && strings.EqualFold(token.Image, "required") && res.Description == "":
+			res.Required = true
+		case token.Type == tokenString:
+			res.Description = unquote(token.Image)
+		default:
+			if res.Description != "" {
+				res.Description += " "
+			}
+			res.Description += token.Image
+		}
+	}
+
+	return &res, nil
+}
+
+func unquote(s string) string {
+	res, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, "\"")
+	}
+	return res
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// @response code [type] ["description"]
+func (p *Parser) response() (*ResponseAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	code := p.consumeAny()
+	if code == nil {
+		return nil, NewParseError(p.column, "expect status code after @response")
+	}
+	if _, err := strconv.Atoi(code.Image); err != nil && !strings.EqualFold(code.Image, "default") {
+		return nil, NewParseError(column, fmt.Sprintf("invalid status code '%s'", code.Image))
+	}
+	var res = ResponseAnnotation{Code: strings.ToLower(code.Image)}
+
+	p.skipWhitespace()
+	if t := p.lookahead(); t != nil && t.Type != tokenString {
+		res.TypeColumn = p.column
+		res.DataType = p.consumeAny().Image
+	}
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		if token.Type == tokenString {
+			res.Description += unquote(token.Image)
+		} else {
+			if res.Description != "" {
+				res.Description += " "
+			}
+			res.Description += token.Image
+		}
+	}
+	return &res, nil
+}
+
+// @request type [contentType]
+func (p *Parser) request() (*RequestAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	dataType, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect type after @request")
+	}
+	var res = RequestAnnotation{DataType: dataType.Image, TypeColumn: column}
+	contentType, err := p.consume(tokenIdentifier)
+	if err == nil {
+		res.ContentType = contentType.Image
+	}
+	return &res, nil
+}
+
+// @min 1 / @max 100
+func (p *Parser) limit(t Type, tag string) (*LimitAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	token := p.consumeValue()
+	if token == nil {
+		return nil, NewParseError(p.column, fmt.Sprintf("expect number after %s", tag))
+	}
+	value, err := strconv.ParseFloat(token.Image, 64)
+	if err != nil {
+		return nil, NewParseError(column, fmt.Sprintf("invalid number '%s'", token.Image))
+	}
+	return &LimitAnnotation{t: t, Value: value}, nil
+}
+
+// @minLength 1 / @maxLength 32
+func (p *Parser) length(t Type, tag string) (*LengthAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	token := p.consumeValue()
+	if token == nil {
+		return nil, NewParseError(p.column, fmt.Sprintf("expect length after %s", tag))
+	}
+	value, err := strconv.ParseUint(token.Image, 10, 64)
+	if err != nil {
+		return nil, NewParseError(column, fmt.Sprintf("invalid length '%s'. expect non-negative integer", token.Image))
+	}
+	return &LengthAnnotation{t: t, Value: value}, nil
+}
+
+// @pattern ^[a-z]+$
+// @pattern "^[a-z ]+$"
+func (p *Parser) pattern() (*PatternAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	text := strings.TrimSpace(p.rest())
+	if text == "" {
+		return nil, NewParseError(column, "expect regular expression after @pattern")
+	}
+	if strings.HasPrefix(text, "\"") {
+		text = unquote(text)
+	}
+	if _, err := regexp.Compile(text); err != nil {
+		return nil, NewParseError(column, fmt.Sprintf("invalid pattern: %s", err))
+	}
+	return &PatternAnnotation{Pattern: text}, nil
+}
+
+// @format email
+func (p *Parser) format() (*FormatAnnotation, error) {
+	format, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect format after @format")
+	}
+	return &FormatAnnotation{Format: format.Image}, nil
+}
+
+// @enum a b "c d"
+func (p *Parser) enum() (*EnumAnnotation, error) {
+	var res = EnumAnnotation{Values: make([]string, 0)}
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		res.Values = append(res.Values, token.Image)
+	}
+	if len(res.Values) == 0 {
+		return nil, NewParseError(p.column, "expect at least one value after @enum")
+	}
+	return &res, nil
+}
+
+// @example foo / @default {"a": 1}
+func (p *Parser) value(t Type, tag string) (*ValueAnnotation, error) {
+	p.skipWhitespace()
+	column := p.column
+	text := strings.TrimSpace(p.rest())
+	if text == "" {
+		return nil, NewParseError(column, fmt.Sprintf("expect value after %s", tag))
+	}
+	if (strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[")) && !json.Valid([]byte(text)) {
+		return nil, NewParseError(column, fmt.Sprintf("invalid JSON value '%s'", text))
+	}
+	return &ValueAnnotation{t: t, Value: text}, nil
+}
+
+// rest consumes all the remaining tokens and returns their text
+func (p *Parser) rest() string {
+	var text string
+	for p.hasMore() {
+		text += p.consumeAny().Image
+	}
+	return text
+}
+
+// @x-name value. value is JSON or plain text. defaults to true if omitted.
+func (p *Parser) extension(tag *Token) (*ExtensionAnnotation, error) {
+	var res = ExtensionAnnotation{Name: strings.TrimPrefix(tag.Image, "@"), Value: true}
+	if len(res.Name) <= len("x-") {
+		return nil, NewParseError(p.column-len(tag.Image), fmt.Sprintf("invalid extension name '%s'", tag.Image))
+	}
+	p.skipWhitespace()
+	column := p.column
+	text := strings.TrimSpace(p.rest())
+	if text == "" {
+		return &res, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		res.Value = value
+	} else if strings.ContainsAny(text[:1], "{[\"") {
+		return nil, NewParseError(column, fmt.Sprintf("invalid JSON value of %s: %s", tag.Image, err))
+	} else {
+		res.Value = text
+	}
+	return &res, nil
+}
+
+// @server url ["description"]
+func (p *Parser) server() (*ServerAnnotation, error) {
+	url, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect url after @server")
+	}
+	var res = ServerAnnotation{URL: url.Image}
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		if res.Description != "" {
+			res.Description += " "
+		}
+		res.Description += unquote(token.Image)
+	}
+	return &res, nil
+}
+
+// @prefix /api/v1
+func (p *Parser) prefix() (*PrefixAnnotation, error) {
+	path, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect path after @prefix")
+	}
+	return &PrefixAnnotation{Path: path.Image}, nil
+}
+
+// @tagDescription name "description"
+// @tagDescription "name with spaces" "description"
+func (p *Parser) tagDescription() (*TagDescriptionAnnotation, error) {
+	name := p.consumeValue()
+	if name == nil {
+		return nil, NewParseError(p.column, "expect tag name after @tagDescription")
+	}
+	var res = TagDescriptionAnnotation{Tag: unquote(name.Image)}
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		if res.Description != "" {
+			res.Description += " "
+		}
+		res.Description += unquote(token.Image)
+	}
+	return &res, nil
+}
+
+// OperationMethods are the HTTP methods accepted by @callback. Webhooks in config accept the same methods
+var OperationMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// @callback name "{$request.body#/url}" METHOD [type] ["description"]
+func (p *Parser) callback() (*CallbackAnnotation, error) {
+	name, err := p.consume(tokenIdentifier)
+	if err != nil {
+		return nil, NewParseError(p.column, "expect name after @callback")
+	}
+	var res = CallbackAnnotation{Name: name.Image}
+
+	expression := p.consumeValue()
+	if expression == nil {
+		return nil, NewParseError(p.column, "expect url expression after callback name")
+	}
+	res.Expression = unquote(expression.Image)
+
+	p.skipWhitespace()
+	column := p.column
+	method := p.consumeValue()
+	if method == nil {
+		return nil, NewParseError(p.column, "expect http method after url expression")
+	}
+	res.Method = strings.ToUpper(method.Image)
+	if !contains(OperationMethods, res.Method) {
+		return nil, NewParseError(column, fmt.Sprintf("invalid http method '%s'", method.Image))
+	}
+
+	p.skipWhitespace()
+	if t := p.lookahead(); t != nil && t.Type != tokenString {
+		res.TypeColumn = p.column
+		res.DataType = p.consumeAny().Image
+	}
+	for p.hasMore() {
+		token := p.consumeValue()
+		if token == nil {
+			break
+		}
+		if res.Description != "" {
+			res.Description += " "
+		}
+		res.Description += unquote(token.Image)
+	}
+	return &res, nil
+}
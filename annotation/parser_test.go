@@ -37,12 +37,218 @@ func TestParser_Parse(t *testing.T) {
 			code: " @security oauth2 pet:read pet:write",
 			want: newSecurityAnnotation("oauth2", []string{"pet:read", "pet:write"}),
 		},
+		{
+			name: "public",
+			code: "@public",
+			want: newSecurityAnnotation(SecurityNone, []string{}),
+		},
+		{
+			name: "internal",
+			code: "@internal",
+			want: newSimpleAnnotation(Internal),
+		},
 		{
 			name:    "security error",
 			code:    "@security",
 			wantErr: true,
 			want:    (*SecurityAnnotation)(nil),
 		},
+		{
+			name: "oneOf",
+			code: "@oneOf pkg.Cat dog=pkg.Dog",
+			want: &OneOfAnnotation{Types: []string{"pkg.Cat", "dog=pkg.Dog"}},
+		},
+		{
+			name:    "oneOf error",
+			code:    "@oneOf",
+			wantErr: true,
+			want:    (*OneOfAnnotation)(nil),
+		},
+		{
+			name: "discriminator",
+			code: "@discriminator kind",
+			want: &DiscriminatorAnnotation{PropertyName: "kind"},
+		},
+		{
+			name: "discriminatorValue",
+			code: "@discriminatorValue 1",
+			want: &DiscriminatorValueAnnotation{Value: "1"},
+		},
+		{
+			name: "param",
+			code: `@param page query integer required "page number"`,
+			want: &ParamAnnotation{Name: "page", In: "query", DataType: "integer", Required: true, Description: "page number"},
+		},
+		{
+			name: "param without description",
+			code: "@param X-Request-Id header []string",
+			want: &ParamAnnotation{Name: "X-Request-Id", In: "header", DataType: "[]string"},
+		},
+		{
+			name:    "param invalid location",
+			code:    "@param page body integer",
+			wantErr: true,
+			want:    (*ParamAnnotation)(nil),
+		},
+		{
+			name:    "param invalid type",
+			code:    "@param page query Page",
+			wantErr: true,
+			want:    (*ParamAnnotation)(nil),
+		},
+		{
+			name: "response",
+			code: `@response 404 pkg.ErrorResp "not found"`,
+			want: &ResponseAnnotation{Code: "404", DataType: "pkg.ErrorResp", Description: "not found", TypeColumn: 14},
+		},
+		{
+			name: "response without type",
+			code: `@response 204 "no content"`,
+			want: &ResponseAnnotation{Code: "204", Description: "no content"},
+		},
+		{
+			name:    "response invalid code",
+			code:    "@response ok pkg.Resp",
+			wantErr: true,
+			want:    (*ResponseAnnotation)(nil),
+		},
+		{
+			name: "request",
+			code: "@request pkg.Page[pkg.User] application/json",
+			want: &RequestAnnotation{DataType: "pkg.Page[pkg.User]", ContentType: "application/json", TypeColumn: 9},
+		},
+		{
+			name: "readOnly",
+			code: "@readOnly",
+			want: newSimpleAnnotation(ReadOnly),
+		},
+		{
+			name: "writeOnly",
+			code: "@writeOnly",
+			want: newSimpleAnnotation(WriteOnly),
+		},
+		{
+			name: "min",
+			code: "@min -1.5",
+			want: &LimitAnnotation{t: Min, Value: -1.5},
+		},
+		{
+			name:    "max error",
+			code:    "@max ten",
+			wantErr: true,
+			want:    (*LimitAnnotation)(nil),
+		},
+		{
+			name: "maxLength",
+			code: "@maxLength 32",
+			want: &LengthAnnotation{t: MaxLength, Value: 32},
+		},
+		{
+			name:    "minLength error",
+			code:    "@minLength -1",
+			wantErr: true,
+			want:    (*LengthAnnotation)(nil),
+		},
+		{
+			name: "pattern",
+			code: `@pattern ^[a-z]+\d*$`,
+			want: &PatternAnnotation{Pattern: `^[a-z]+\d*$`},
+		},
+		{
+			name: "quoted pattern",
+			code: `@pattern "^[a-z ]+$"`,
+			want: &PatternAnnotation{Pattern: `^[a-z ]+$`},
+		},
+		{
+			name:    "pattern error",
+			code:    "@pattern ^[a-z+$",
+			wantErr: true,
+			want:    (*PatternAnnotation)(nil),
+		},
+		{
+			name: "format",
+			code: "@format email",
+			want: &FormatAnnotation{Format: "email"},
+		},
+		{
+			name: "enum",
+			code: `@enum 1 two "three four"`,
+			want: &EnumAnnotation{Values: []string{"1", "two", `"three four"`}},
+		},
+		{
+			name: "example",
+			code: `@example {"a": 1}`,
+			want: &ValueAnnotation{t: Example, Value: `{"a": 1}`},
+		},
+		{
+			name:    "default error",
+			code:    `@default {"a": }`,
+			wantErr: true,
+			want:    (*ValueAnnotation)(nil),
+		},
+		{
+			name: "nullable",
+			code: "@nullable",
+			want: newSimpleAnnotation(Nullable),
+		},
+		{
+			name: "extension",
+			code: `@x-rate-limit {"limit": 100, "window": "1m"}`,
+			want: &ExtensionAnnotation{Name: "x-rate-limit", Value: map[string]interface{}{"limit": float64(100), "window": "1m"}},
+		},
+		{
+			name: "extension scalar",
+			code: "@x-codegen-name listUsers",
+			want: &ExtensionAnnotation{Name: "x-codegen-name", Value: "listUsers"},
+		},
+		{
+			name: "extension flag",
+			code: "@x-internal",
+			want: &ExtensionAnnotation{Name: "x-internal", Value: true},
+		},
+		{
+			name:    "extension invalid JSON",
+			code:    `@x-rate-limit {"limit": }`,
+			wantErr: true,
+			want:    (*ExtensionAnnotation)(nil),
+		},
+		{
+			name: "server",
+			code: `@server https://{region}.example.com "regional server"`,
+			want: &ServerAnnotation{URL: "https://{region}.example.com", Description: "regional server"},
+		},
+		{
+			name: "prefix",
+			code: "@prefix /api/v1",
+			want: &PrefixAnnotation{Path: "/api/v1"},
+		},
+		{
+			name:    "prefix error",
+			code:    "@prefix",
+			wantErr: true,
+			want:    (*PrefixAnnotation)(nil),
+		},
+		{
+			name: "tagDescription",
+			code: `@tagDescription "High Priority" "接口 描述"`,
+			want: &TagDescriptionAnnotation{Tag: "High Priority", Description: "接口 描述"},
+		},
+		{
+			name: "tagDescription without quotes",
+			code: "@tagDescription Shop 商城 接口",
+			want: &TagDescriptionAnnotation{Tag: "Shop", Description: "商城 接口"},
+		},
+		{
+			name: "callback",
+			code: `@callback onEvent "{$request.body#/url}" post pkg.Event "event notification"`,
+			want: &CallbackAnnotation{Name: "onEvent", Expression: "{$request.body#/url}", Method: "POST", DataType: "pkg.Event", Description: "event notification", TypeColumn: 46},
+		},
+		{
+			name:    "callback invalid method",
+			code:    `@callback onEvent "{$request.body#/url}" SEND pkg.Event`,
+			wantErr: true,
+			want:    (*CallbackAnnotation)(nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,51 @@
+package annotation
+
+import (
+	"strings"
+)
+
+// KnownTags is the list of annotations supported by the parser
+var KnownTags = []string{
+	"@required", "@consume", "@produce", "@ignore", "@tag", "@tags", "@description", "@summary", "@id",
+	"@deprecated", "@security", "@public", "@readOnly", "@writeOnly", "@param", "@response", "@request",
+	"@min", "@max", "@minLength", "@maxLength", "@pattern", "@format", "@enum", "@example", "@default", "@nullable",
+	"@oneOf", "@discriminator", "@discriminatorValue", "@server", "@prefix",
+	"@tagDescription", "@callback", "@internal",
+}
+
+// Suggest returns the known annotation which is most similar to tag. Returns empty string if none of them is similar enough.
+func Suggest(tag string) string {
+	tag = strings.ToLower(tag)
+	var res string
+	// at most 2 edits, and less than half of the tag
+	var best = min(len(tag)/2+1, 3)
+	for _, known := range KnownTags {
+		d := distance(tag, strings.ToLower(known))
+		if d < best {
+			res, best = known, d
+		}
+	}
+	return res
+}
+
+// distance returns levenshtein distance between a and b
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
@@ -0,0 +1,26 @@
+package annotation
+
+import (
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "@sumary", want: "@summary"},
+		{tag: "@securty", want: "@security"},
+		{tag: "@Descripton", want: "@description"},
+		{tag: "@minlenght", want: "@minLength"},
+		{tag: "@router", want: ""},
+		{tag: "@ab", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := Suggest(tt.tag); got != tt.want {
+				t.Errorf("Suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
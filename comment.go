@@ -1,10 +1,12 @@
 package eapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/chenwei67/eapi/annotation"
@@ -15,6 +17,10 @@ import (
 type Comment struct {
 	text        string
 	Annotations []annotation.Annotation
+
+	fSet *token.FileSet
+	// position of the comment line which each annotation is written in
+	positions map[annotation.Annotation]token.Pos
 }
 
 func (c *Comment) Text() string {
@@ -64,22 +70,163 @@ func (c *Comment) Deprecated() bool {
 	return false
 }
 
+func (c *Comment) ReadOnly() bool {
+	return c.hasAnnotation(annotation.ReadOnly)
+}
+
+func (c *Comment) WriteOnly() bool {
+	return c.hasAnnotation(annotation.WriteOnly)
+}
+
+// Internal reports whether the operation or field is declared as internal by @internal annotation
+func (c *Comment) Internal() bool {
+	return c.hasAnnotation(annotation.Internal)
+}
+
+func (c *Comment) hasAnnotation(t annotation.Type) bool {
+	if c == nil {
+		return false
+	}
+	for _, a := range c.Annotations {
+		if a.Type() == t {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Comment) ApplyToSchema(schema *spec.SchemaRef) {
 	if c == nil || schema == nil {
 		return
 	}
+	schema.ReadOnly = schema.ReadOnly || c.ReadOnly()
+	schema.WriteOnly = schema.WriteOnly || c.WriteOnly()
+	c.ApplyExtensions(&schema.ExtensionProps)
+	if c.Internal() {
+		markInternal(&schema.ExtensionProps)
+	}
 	if schema.Ref != "" {
-		schema.Description = c.Text()
+		// keywords other than summary and description are kept next to the reference,
+		// which is wrapped by allOf in output if necessary
 		schema.Summary = c.Summary()
-		return
 	}
+	schema.Description = c.Text()
+	schema.Deprecated = c.Deprecated()
+	schema.Nullable = schema.Nullable || c.hasAnnotation(annotation.Nullable)
+	c.applyConstraints(schema)
+}
+
+// applyConstraints applies @min @max @minLength @maxLength @pattern @format @enum @example @default to schema.
+// Constraints of array fields are applied to their items.
+func (c *Comment) applyConstraints(schema *spec.Schema) {
+	target := schema
+	if schema.Type == spec.TypeArray && schema.Items != nil {
+		target = schema.Items
+	}
+	for _, annot := range c.Annotations {
+		switch annot := annot.(type) {
+		case *annotation.LimitAnnotation:
+			if annot.Type() == annotation.Min {
+				target.WithMin(annot.Value)
+			} else {
+				target.WithMax(annot.Value)
+			}
+		case *annotation.LengthAnnotation:
+			if annot.Type() == annotation.MinLength {
+				target.WithMinLength(int64(annot.Value))
+			} else {
+				target.WithMaxLength(int64(annot.Value))
+			}
+		case *annotation.PatternAnnotation:
+			target.Pattern = annot.Pattern
+		case *annotation.FormatAnnotation:
+			target.Format = annot.Format
+		case *annotation.EnumAnnotation:
+			var values []interface{}
+			for _, value := range annot.Values {
+				values = append(values, annotationValueOf(target, value))
+			}
+			target.WithEnum(values...)
+		case *annotation.ValueAnnotation:
+			if annot.Type() == annotation.Example {
+				schema.Example = annotationValueOf(schema, annot.Value)
+			} else {
+				schema.Default = annotationValueOf(schema, annot.Value)
+			}
+		}
+	}
+}
 
-	value := schema
-	if value == nil {
+// annotationValueOf converts raw value written in annotations according to type of schema.
+// Quoted values are always strings. Falls back to the raw text if it can not be converted.
+func annotationValueOf(schema *spec.Schema, raw string) interface{} {
+	if strings.HasPrefix(raw, "\"") {
+		if value, err := strconv.Unquote(raw); err == nil {
+			return value
+		}
+		return strings.Trim(raw, "\"")
+	}
+	switch schema.Type {
+	case spec.TypeInteger:
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value
+		}
+	case spec.TypeNumber:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case spec.TypeBoolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case spec.TypeString:
+		return raw
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err == nil {
+		return value
+	}
+	return raw
+}
+
+// Extensions returns vendor extensions declared by @x-* annotations
+func (c *Comment) Extensions() map[string]interface{} {
+	if c == nil {
+		return nil
+	}
+	var res map[string]interface{}
+	for _, annot := range c.Annotations {
+		ext, ok := annot.(*annotation.ExtensionAnnotation)
+		if ok {
+			if res == nil {
+				res = make(map[string]interface{})
+			}
+			res[ext.Name] = ext.Value
+		}
+	}
+	return res
+}
+
+// markInternal marks the operation, parameter or schema with spec.ExtensionInternal
+func markInternal(props *spec.ExtensionProps) {
+	if props.Extensions == nil {
+		props.Extensions = make(map[string]interface{})
+	}
+	props.Extensions[spec.ExtensionInternal] = true
+}
+
+// ApplyExtensions merges vendor extensions declared by @x-* annotations into props
+func (c *Comment) ApplyExtensions(props *spec.ExtensionProps) {
+	extensions := c.Extensions()
+	if len(extensions) == 0 {
 		return
 	}
-	value.Description = c.Text()
-	value.Deprecated = c.Deprecated()
+	if props.Extensions == nil {
+		props.Extensions = make(map[string]interface{}, len(extensions))
+	}
+	for name, value := range extensions {
+		props.Extensions[name] = value
+	}
 }
 
 func (c *Comment) Consumes() []string {
@@ -157,6 +304,121 @@ func (c *Comment) ID() string {
 	return ""
 }
 
+// OneOf returns candidate types declared by @oneOf annotation
+func (c *Comment) OneOf() []string {
+	if c == nil {
+		return nil
+	}
+	var res []string
+	for _, annot := range c.Annotations {
+		oneOf, ok := annot.(*annotation.OneOfAnnotation)
+		if ok {
+			res = append(res, oneOf.Types...)
+		}
+	}
+	return res
+}
+
+func (c *Comment) Discriminator() string {
+	if c == nil {
+		return ""
+	}
+	for _, annot := range c.Annotations {
+		discriminator, ok := annot.(*annotation.DiscriminatorAnnotation)
+		if ok {
+			return discriminator.PropertyName
+		}
+	}
+	return ""
+}
+
+func (c *Comment) DiscriminatorValue() string {
+	if c == nil {
+		return ""
+	}
+	for _, annot := range c.Annotations {
+		value, ok := annot.(*annotation.DiscriminatorValueAnnotation)
+		if ok {
+			return value.Value
+		}
+	}
+	return ""
+}
+
+func (c *Comment) Params() []*annotation.ParamAnnotation {
+	if c == nil {
+		return nil
+	}
+	var res []*annotation.ParamAnnotation
+	for _, annot := range c.Annotations {
+		param, ok := annot.(*annotation.ParamAnnotation)
+		if ok {
+			res = append(res, param)
+		}
+	}
+	return res
+}
+
+func (c *Comment) Responses() []*annotation.ResponseAnnotation {
+	if c == nil {
+		return nil
+	}
+	var res []*annotation.ResponseAnnotation
+	for _, annot := range c.Annotations {
+		response, ok := annot.(*annotation.ResponseAnnotation)
+		if ok {
+			res = append(res, response)
+		}
+	}
+	return res
+}
+
+// Request returns the first @request annotation
+func (c *Comment) Request() *annotation.RequestAnnotation {
+	if c == nil {
+		return nil
+	}
+	for _, annot := range c.Annotations {
+		request, ok := annot.(*annotation.RequestAnnotation)
+		if ok {
+			return request
+		}
+	}
+	return nil
+}
+
+func (c *Comment) Callbacks() []*annotation.CallbackAnnotation {
+	if c == nil {
+		return nil
+	}
+	var res []*annotation.CallbackAnnotation
+	for _, annot := range c.Annotations {
+		callback, ok := annot.(*annotation.CallbackAnnotation)
+		if ok {
+			res = append(res, callback)
+		}
+	}
+	return res
+}
+
+func (c *Comment) Servers() spec.Servers {
+	if c == nil {
+		return nil
+	}
+	return convertServerAnnotations(c.Annotations)
+}
+
+func convertServerAnnotations(annotations []annotation.Annotation) spec.Servers {
+	var res spec.Servers
+	for _, annot := range annotations {
+		server, ok := annot.(*annotation.ServerAnnotation)
+		if ok {
+			res = append(res, &spec.Server{URL: server.URL, Description: server.Description})
+		}
+	}
+	return res
+}
+
 func (c *Comment) Security() *spec.SecurityRequirements {
 	if c == nil {
 		return nil
@@ -165,21 +427,100 @@ func (c *Comment) Security() *spec.SecurityRequirements {
 	return convertSecAnnotationToSecurityRequirements(c.Annotations)
 }
 
+// convertSecAnnotationToSecurityRequirements returns nil if there is no @security annotation.
+// "@security none" or "@public" results in empty requirements which overrides the global security.
 func convertSecAnnotationToSecurityRequirements(annotations []annotation.Annotation) *spec.SecurityRequirements {
 	ret := spec.NewSecurityRequirements()
+	public := false
 	for _, annot := range annotations {
 		annot, ok := annot.(*annotation.SecurityAnnotation)
-		if ok {
-			ret.With(spec.NewSecurityRequirement().Authenticate(annot.Name, annot.Params...))
+		if !ok {
+			continue
 		}
+		if annot.Name == annotation.SecurityNone {
+			public = true
+			continue
+		}
+		ret.With(spec.NewSecurityRequirement().Authenticate(annot.Name, annot.Params...))
 	}
-	if len(*ret) == 0 {
+	if len(*ret) == 0 && !public {
 		return nil
 	}
 
 	return ret
 }
 
+// ReportError reports error of the annotation with its position
+func (c *Comment) ReportError(ctx *Context, annot annotation.Annotation, err *annotation.ParseError) {
+	pos := c.positions[annot]
+	if ctx != nil && c.fSet != nil && pos.IsValid() {
+		// annotations inherited from file or package comments may be resolved many times
+		if !ctx.markReported(c.fSet.Position(pos+token.Pos(err.Column)).String() + err.Message) {
+			return
+		}
+	}
+	reportAnnotationError(ctx, c.fSet, pos, err)
+}
+
+func reportAnnotationError(ctx *Context, fSet *token.FileSet, pos token.Pos, err *annotation.ParseError) {
+	errorMsg := fmt.Sprintf("[Invalid Annotation]: %s", err)
+	if fSet != nil && pos.IsValid() {
+		errorMsg += " at " + fSet.Position(pos+token.Pos(err.Column)).String()
+	}
+	if ctx != nil {
+		ctx.StrictError(errorMsg)
+	} else {
+		fmt.Fprintf(os.Stderr, errorMsg+"\n")
+	}
+}
+
+// ReportUnknownAnnotations reports annotations which can not be recognized (e.g. typo like "@sumary").
+// owner describes what the comment is attached to. e.g. "operation GET /users"
+func (c *Comment) ReportUnknownAnnotations(ctx *Context, owner string) {
+	if c == nil || ctx == nil {
+		return
+	}
+	for _, annot := range c.Annotations {
+		unresolved, ok := annot.(*annotation.UnresolvedAnnotation)
+		if !ok {
+			continue
+		}
+		var location string
+		if pos := c.positions[annot]; c.fSet != nil && pos.IsValid() {
+			location = c.fSet.Position(pos + token.Pos(unresolved.Column)).String()
+			if !ctx.markReported(location) {
+				continue // the same comment may be parsed more than once
+			}
+		}
+
+		msg := fmt.Sprintf("[Unknown Annotation]: %s", unresolved.Tag)
+		if suggestion := annotation.Suggest(unresolved.Tag); suggestion != "" {
+			msg += fmt.Sprintf(", did you mean %s?", suggestion)
+		}
+		if owner != "" {
+			msg += " on " + owner
+		}
+		if location != "" {
+			msg += " at " + location
+		}
+		ctx.WarnOrError("%s", msg)
+	}
+}
+
+// joinCommentGroups joins non-nil comment groups into one. Returns nil if all of them are nil
+func joinCommentGroups(groups ...*ast.CommentGroup) *ast.CommentGroup {
+	var list []*ast.Comment
+	for _, group := range groups {
+		if group != nil {
+			list = append(list, group.List...)
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return &ast.CommentGroup{List: list}
+}
+
 func ParseComment(commentGroup *ast.CommentGroup, fSet *token.FileSet) *Comment {
 	return ParseCommentWithContext(commentGroup, fSet, nil)
 }
@@ -188,23 +529,18 @@ func ParseCommentWithContext(commentGroup *ast.CommentGroup, fSet *token.FileSet
 	if commentGroup == nil {
 		return nil
 	}
-	c := &Comment{}
+	c := &Comment{fSet: fSet, positions: make(map[annotation.Annotation]token.Pos)}
 	var lines []string
 	var descriptions []*annotation.DescriptionAnnotation
 	for _, comment := range commentGroup.List {
 		annot, err := annotation.NewParser(comment.Text).Parse()
 		if err != nil {
-			err := err.(*annotation.ParseError)
-			errorMsg := fmt.Sprintf("[Invalid Annotation]: %s at %s", err, fSet.Position(comment.Pos()+token.Pos(err.Column)).String())
-			if ctx != nil {
-				ctx.StrictError(errorMsg)
-			} else {
-				fmt.Fprintf(os.Stderr, errorMsg+"\n")
-			}
+			reportAnnotationError(ctx, fSet, comment.Pos(), err.(*annotation.ParseError))
 			continue
 		}
 		if annot != nil {
 			c.Annotations = append(c.Annotations, annot)
+			c.positions[annot] = comment.Pos()
 			desc, ok := annot.(*annotation.DescriptionAnnotation)
 			if ok {
 				descriptions = append(descriptions, desc)
@@ -0,0 +1,32 @@
+package eapi
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func newComment(lines ...string) *Comment {
+	group := &ast.CommentGroup{}
+	for _, line := range lines {
+		group.List = append(group.List, &ast.Comment{Text: "// " + line})
+	}
+	return ParseComment(group, token.NewFileSet())
+}
+
+func TestComment_ApplyToSchema_Direction(t *testing.T) {
+	// existing values are kept if the comment has no @readOnly / @writeOnly annotation
+	schema := &spec.Schema{Ref: "#/components/schemas/User", ReadOnly: true}
+	newComment("Creator").ApplyToSchema(schema)
+	assert.True(t, schema.ReadOnly)
+	assert.False(t, schema.WriteOnly)
+	assert.Equal(t, "Creator", schema.Description)
+
+	schema = spec.NewStringSchema()
+	newComment("Password", "@writeOnly").ApplyToSchema(schema)
+	assert.False(t, schema.ReadOnly)
+	assert.True(t, schema.WriteOnly)
+}
@@ -3,12 +3,17 @@ package eapi
 import (
 	"fmt"
 	"go/ast"
+	"go/parser"
 	"go/token"
 	"go/types"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/chenwei67/eapi/spec"
 	"github.com/chenwei67/eapi/utils"
+	"github.com/samber/lo"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -85,14 +90,183 @@ func (c *Context) ParseType(t types.Type) Definition {
 	return nil
 }
 
+// ParseTypeExpr resolves type expression written in comments (e.g. "pkg.User", "[]*User" or "pkg.Page[pkg.User]")
+// in the scope of current file.
+func (c *Context) ParseTypeExpr(text string) (types.Type, error) {
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type expression '%s'", text)
+	}
+	return c.typeOfExpr(expr)
+}
+
+func (c *Context) typeOfExpr(expr ast.Expr) (types.Type, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		obj := c.Package().Types.Scope().Lookup(expr.Name)
+		if obj == nil {
+			obj = types.Universe.Lookup(expr.Name)
+		}
+		return c.typeOfObject(obj, expr.Name)
+	case *ast.SelectorExpr:
+		x, ok := expr.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		pkg, err := c.lookupImportedPackage(x.Name)
+		if err != nil {
+			return nil, err
+		}
+		return c.typeOfObject(pkg.Types.Scope().Lookup(expr.Sel.Name), x.Name+"."+expr.Sel.Name)
+	case *ast.StarExpr:
+		elem, err := c.typeOfExpr(expr.X)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewPointer(elem), nil
+	case *ast.ArrayType:
+		elem, err := c.typeOfExpr(expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewSlice(elem), nil
+	case *ast.MapType:
+		key, err := c.typeOfExpr(expr.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := c.typeOfExpr(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, value), nil
+	case *ast.IndexExpr:
+		return c.instantiate(expr.X, []ast.Expr{expr.Index})
+	case *ast.IndexListExpr:
+		return c.instantiate(expr.X, expr.Indices)
+	}
+
+	return nil, fmt.Errorf("unsupported type expression '%s'", types.ExprString(expr))
+}
+
+func (c *Context) typeOfObject(obj types.Object, name string) (types.Type, error) {
+	typeName, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type '%s' not found", name)
+	}
+	return typeName.Type(), nil
+}
+
+func (c *Context) instantiate(genericExpr ast.Expr, argExprs []ast.Expr) (types.Type, error) {
+	generic, err := c.typeOfExpr(genericExpr)
+	if err != nil {
+		return nil, err
+	}
+	var args []types.Type
+	for _, argExpr := range argExprs {
+		arg, err := c.typeOfExpr(argExpr)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	res, err := types.Instantiate(nil, generic, args, true)
+	if err != nil {
+		return nil, fmt.Errorf("instantiate '%s' failed: %w", types.ExprString(genericExpr), err)
+	}
+	return res, nil
+}
+
+// lookupImportedPackage finds package by name. Imports of current file take precedence,
+// then all the packages which are (indirectly) imported by current package.
+func (c *Context) lookupImportedPackage(name string) (*packages.Package, error) {
+	if c.File() != nil {
+		for _, imp := range c.File().Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			pkg := c.Package().Imports[path]
+			if pkg == nil {
+				continue
+			}
+			if imp.Name != nil && imp.Name.Name == name || imp.Name == nil && pkg.Name == name {
+				return pkg, nil
+			}
+		}
+	}
+
+	candidates := make(map[string]*packages.Package)
+	InspectPackage(c.Package(), func(pkg *packages.Package) bool {
+		if _, ok := candidates[pkg.PkgPath]; ok {
+			return false
+		}
+		if pkg.Name == name {
+			candidates[pkg.PkgPath] = pkg
+		}
+		return true
+	})
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("package '%s' not found", name)
+	case 1:
+		return lo.Values(candidates)[0], nil
+	}
+	return nil, fmt.Errorf("package name '%s' is ambiguous: %s", name, strings.Join(lo.Keys(candidates), ", "))
+}
+
+// ImplementationsOf returns all the types in analyzed module which implement the given interface
+func (c *Context) ImplementationsOf(iface *types.Named) []*TypeDefinition {
+	underlying, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var res []*TypeDefinition
+	for _, def := range c.analyzer.definitions {
+		typeDef, ok := def.(*TypeDefinition)
+		if !ok || typeDef.Spec.TypeParams != nil {
+			continue
+		}
+		obj := typeDef.pkg.Types.Scope().Lookup(typeDef.Spec.Name.Name)
+		if obj == nil || obj.Type() == iface {
+			continue
+		}
+		if _, ok := obj.Type().Underlying().(*types.Interface); ok {
+			continue
+		}
+		if types.Implements(obj.Type(), underlying) || types.Implements(types.NewPointer(obj.Type()), underlying) {
+			res = append(res, typeDef)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key() < res[j].Key() })
+	return res
+}
+
 func (c *Context) Doc() *spec.T {
 	return c.analyzer.Doc()
 }
 
 func (c *Context) AddAPI(items ...*API) {
+	if c.commentStack != nil {
+		if prefix := c.commentStack.LookupPrefix(); prefix != "" {
+			for _, item := range items {
+				item.FullPath = joinPrefix(prefix, item.FullPath)
+			}
+		}
+	}
+	for _, item := range items {
+		item.pkg = c.pkg.PkgPath
+		if item.Spec != nil && item.Spec.pkg == "" {
+			item.Spec.pkg = c.pkg.PkgPath
+		}
+	}
 	c.analyzer.AddRoutes(items...)
 }
 
+// joinPrefix prepends the path prefix declared by @prefix to fullPath. Unlike path.Join, trailing slash of fullPath
+// is kept since "/users/" and "/users" are different routes
+func joinPrefix(prefix, fullPath string) string {
+	return strings.TrimSuffix(path.Join("/", prefix), "/") + "/" + strings.TrimPrefix(fullPath, "/")
+}
+
 func (c *Context) ParseStatusCode(status ast.Expr) int {
 	switch status := status.(type) {
 	case *ast.SelectorExpr:
@@ -189,6 +363,27 @@ func (c *Context) StrictWarn(format string, args ...interface{}) {
 	LogStrictWarn(format, args...)
 }
 
+// WarnOrError prints warning message, or error message in strict mode
+func (c *Context) WarnOrError(format string, args ...interface{}) {
+	if c.analyzer != nil && c.analyzer.strictMode {
+		LogError(format, args...)
+	} else {
+		LogWarn(format, args...)
+	}
+}
+
+// markReported records the diagnostic at position. Returns false if it has been reported before.
+func (c *Context) markReported(position string) bool {
+	if c.analyzer == nil {
+		return true
+	}
+	if _, ok := c.analyzer.reported[position]; ok {
+		return false
+	}
+	c.analyzer.reported[position] = struct{}{}
+	return true
+}
+
 type CallRule struct {
 	Rules map[string][]string // typeName to function-names
 }
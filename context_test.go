@@ -0,0 +1,25 @@
+package eapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinPrefix(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		fullPath string
+		want     string
+	}{
+		{prefix: "/api", fullPath: "/users", want: "/api/users"},
+		{prefix: "/api", fullPath: "/users/", want: "/api/users/"},
+		{prefix: "api/", fullPath: "users", want: "/api/users"},
+		{prefix: "/api/v1/../v2", fullPath: "/users", want: "/api/v2/users"},
+		{prefix: "/api", fullPath: "/", want: "/api/"},
+		{prefix: "/", fullPath: "/users", want: "/users"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, joinPrefix(tt.prefix, tt.fullPath), "%s + %s", tt.prefix, tt.fullPath)
+	}
+}
@@ -80,6 +80,7 @@ type TypeDefinition struct {
 
 	pkg  *packages.Package
 	file *ast.File
+	decl *ast.GenDecl
 }
 
 func NewTypeDefinition(pkg *packages.Package, file *ast.File, spec *ast.TypeSpec) *TypeDefinition {
@@ -96,6 +97,16 @@ func (t *TypeDefinition) File() *ast.File {
 	return t.file
 }
 
+// CommentGroup returns the doc comment (of the TypeSpec, or of the GenDecl if it declares only this type)
+// followed by the trailing comment of the type
+func (t *TypeDefinition) CommentGroup() *ast.CommentGroup {
+	doc := t.Spec.Doc
+	if doc == nil && t.decl != nil && len(t.decl.Specs) == 1 {
+		doc = t.decl.Doc
+	}
+	return joinCommentGroups(doc, t.Spec.Comment)
+}
+
 func (t *TypeDefinition) Key() string {
 	return t.pkg.PkgPath + "." + t.Spec.Name.Name
 }
@@ -0,0 +1,189 @@
+package eapi
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/samber/lo"
+	"golang.org/x/tools/go/packages"
+)
+
+// DocumentConfig declares a separate document which contains part of the routes. e.g. routes of one service in monorepo.
+// Selectors of different kinds are combined with AND, and values of the same selector are combined with OR.
+// All the routes are selected if no selector is set.
+type DocumentConfig struct {
+	// Required. File name of the document. Extension is appended by format
+	OutputFile string `yaml:"outputFile"`
+	// Select routes registered by the packages (usually main packages) and the packages imported by them.
+	// Values are import paths or directories relative to 'dir'. e.g. "./cmd/app-a"
+	Packages []string
+	// Select routes whose path starts with any of the prefixes
+	PathPrefixes []string `yaml:"pathPrefixes"`
+	// Select operations which have any of the tags
+	Tags []string
+	// Overrides the top-level 'openapi' config
+	OpenAPI OpenAPIConfig
+}
+
+func (e *Entrypoint) validateDocuments() error {
+	rootDirs := make(map[string]string)
+	for i, item := range e.cfg.Documents {
+		if item == nil || item.OutputFile == "" {
+			return fmt.Errorf("invalid documents[%d]: 'outputFile' is required", i)
+		}
+		if len(item.OpenAPI.Webhooks) > 0 {
+			return fmt.Errorf("invalid documents[%d]: webhooks can only be declared in the top-level 'openapi'", i)
+		}
+		err := item.OpenAPI.validate()
+		if err != nil {
+			return fmt.Errorf("invalid documents[%d]: %w", i, err)
+		}
+		// split files are written into directory of the root file
+		if e.cfg.Split != nil {
+			dir := path.Dir(item.OutputFile)
+			if other, ok := rootDirs[dir]; ok {
+				return fmt.Errorf("invalid documents[%d]: split documents %q and %q must be written into different directories", i, other, item.OutputFile)
+			}
+			rootDirs[dir] = item.OutputFile
+		}
+		if documents, ok := e.k.Get("documents").([]interface{}); ok && i < len(documents) {
+			if document, ok := documents[i].(map[string]interface{}); ok {
+				item.OpenAPI.Extensions = extensionsOf(document["openapi"])
+			}
+		}
+	}
+	return nil
+}
+
+// documentTags returns tags declared in the top-level 'openapi' config and in the config of each document
+func (c *Config) documentTags() []*TagConfig {
+	res := append([]*TagConfig{}, c.OpenAPI.Tags...)
+	for _, item := range c.Documents {
+		res = append(res, item.OpenAPI.Tags...)
+	}
+	return res
+}
+
+// build returns the document which contains only the selected operations of doc
+func (c *DocumentConfig) build(doc *spec.T, a *Analyzer, dir string) (*spec.T, error) {
+	var routes map[string]struct{}
+	if len(c.Packages) > 0 {
+		pkgs, err := a.importedPackages(c.Packages, dir)
+		if err != nil {
+			return nil, err
+		}
+		routes = make(map[string]struct{})
+		for _, route := range a.routes {
+			if _, ok := pkgs[route.pkg]; ok {
+				routes[strings.ToUpper(route.Method)+" "+route.FullPath] = struct{}{}
+			}
+		}
+	}
+
+	return doc.FilterOperations(func(path, method string, operation *spec.Operation) bool {
+		if routes != nil {
+			if _, ok := routes[method+" "+path]; !ok {
+				return false
+			}
+		}
+		if len(c.PathPrefixes) > 0 && !lo.SomeBy(c.PathPrefixes, func(prefix string) bool { return strings.HasPrefix(path, prefix) }) {
+			return false
+		}
+		if len(c.Tags) > 0 && !lo.Some(operation.Tags, c.Tags) {
+			return false
+		}
+		return true
+	}), nil
+}
+
+// importedPackages returns import paths of the packages matched by selectors and the packages (in the same module)
+// imported by them. Selectors are import paths or directories relative to dir
+func (a *Analyzer) importedPackages(selectors []string, dir string) (map[string]struct{}, error) {
+	res := make(map[string]struct{})
+	for _, selector := range selectors {
+		var matchDir string
+		if strings.HasPrefix(selector, ".") || filepath.IsAbs(selector) {
+			matchDir = selector
+			if !filepath.IsAbs(matchDir) {
+				matchDir = filepath.Join(dir, selector)
+			}
+			var err error
+			matchDir, err = filepath.Abs(matchDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var found bool
+		for _, pkg := range a.packages {
+			if matchDir != "" && packageDir(pkg) != matchDir || matchDir == "" && pkg.PkgPath != selector {
+				continue
+			}
+			found = true
+			if pkg.Module == nil {
+				res[pkg.PkgPath] = struct{}{}
+				continue
+			}
+			moduleDir := pkg.Module.Dir
+			InspectPackage(pkg, func(pkg *packages.Package) bool {
+				if _, ok := res[pkg.PkgPath]; ok {
+					return false
+				}
+				if pkg.Module == nil || pkg.Module.Dir != moduleDir {
+					return false
+				}
+				res[pkg.PkgPath] = struct{}{}
+				return true
+			})
+		}
+		if !found {
+			return nil, fmt.Errorf("package %q is not found", selector)
+		}
+	}
+	return res, nil
+}
+
+func packageDir(pkg *packages.Package) string {
+	if len(pkg.Syntax) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.Fset.Position(pkg.Syntax[0].Package).Filename)
+}
+
+// override returns copy of c with fields overridden by the non-empty fields of o
+func (c OpenAPIConfig) override(o OpenAPIConfig) OpenAPIConfig {
+	res := c
+	if o.OpenAPI != "" {
+		res.OpenAPI = o.OpenAPI
+	}
+	if o.Info != nil {
+		info := &spec.Info{}
+		if c.Info != nil {
+			mergeInfo(info, c.Info)
+		}
+		mergeInfo(info, o.Info)
+		res.Info = info
+	}
+	if len(o.Servers) > 0 {
+		res.Servers = o.Servers
+	}
+	if o.ExternalDocs != nil {
+		res.ExternalDocs = o.ExternalDocs
+	}
+	if o.SecuritySchemes != nil {
+		res.SecuritySchemes = o.SecuritySchemes
+	}
+	if o.Security != nil {
+		res.Security = o.Security
+	}
+	if len(o.Tags) > 0 {
+		res.Tags = o.Tags
+	}
+	if len(o.Extensions) > 0 {
+		res.Extensions = lo.Assign(c.Extensions, o.Extensions)
+	}
+	return res
+}
@@ -1,16 +1,21 @@
 package eapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
+	"strings"
 
 	"github.com/chenwei67/eapi/spec"
+	"github.com/chenwei67/eapi/utils"
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/urfave/cli/v2"
 )
@@ -22,36 +27,88 @@ type Config struct {
 	Depends    []string
 	StrictMode bool
 	LogLevel   string `yaml:"logLevel"`
-	OpenAPI    OpenAPIConfig
+	// Format of the documentation: json | yaml | both. Default to json
+	OutputFormat string `yaml:"outputFormat"`
+	// File name of the documentation. Default to "openapi". Extension is appended by format
+	OutputFile string `yaml:"outputFile"`
+	// Also write Swagger 2.0 documentation (swagger.json)
+	Swagger2 bool `yaml:"swagger2"`
+	// Split documentation into multiple files which are referenced by relative $ref
+	Split *SplitConfig `yaml:"split"`
+	// Write a separate document for each item instead of the documentation of all the routes
+	Documents []*DocumentConfig
+	// Additional documents filtered from each written document. e.g. public document
+	Variants []*VariantConfig
+	OpenAPI  OpenAPIConfig
+	// 请求与响应共用且包含 readOnly/writeOnly 字段的模型，分别生成 XxxInput/XxxOutput 两个模型
+	SplitSchemasByDirection bool `yaml:"splitSchemasByDirection"`
+	// 组件模型命名规则
+	SchemaNaming *SchemaNamingConfig `yaml:"schemaNaming"`
+	// 移除没有被接口、回调及其他组件引用的模型
+	PruneSchemas bool `yaml:"pruneSchemas"`
+	// 合并结构完全相同的模型
+	DedupeSchemas bool `yaml:"dedupeSchemas"`
+	// 为接口和模型添加 x-go-source（文件、行号、函数）及 x-go-type 扩展，文件路径相对于 module 根目录
+	SourceLocation bool `yaml:"sourceLocation"`
+	// Overlay 文档或 JSON Merge Patch 文件, 在生成的文档上按顺序应用
+	Overlays []string
 
 	Generators []*GeneratorConfig
 }
 
 type OpenAPIConfig struct {
-	OpenAPI         string           `yaml:"openapi"` // OpenAPI version 3.0.0|3.0.3|3.1.0
-	Info            *spec.Info       `yaml:"info"`    // Required
-	SecuritySchemes *SecuritySchemes `yaml:"securitySchemes"`
+	OpenAPI         string             `yaml:"openapi"` // OpenAPI version 3.0.0|3.0.3|3.1.0
+	Info            *spec.Info         `yaml:"info"`    // Required
+	Servers         spec.Servers       `yaml:"servers"`
+	ExternalDocs    *spec.ExternalDocs `yaml:"externalDocs"`
+	SecuritySchemes *SecuritySchemes   `yaml:"securitySchemes"`
+	// Global security requirements. Operations can opt out with "@security none" or "@public"
+	Security *spec.SecurityRequirements `yaml:"security"`
+	// Descriptions and order of tags
+	Tags []*TagConfig `yaml:"tags"`
+	// Webhooks of OpenAPI 3.1
+	Webhooks []*WebhookConfig `yaml:"webhooks"`
+	// Top-level specification extensions. Collected from keys starting with "x-"
+	Extensions map[string]interface{} `yaml:"-"`
 }
 
 type SecuritySchemes map[string]*spec.SecurityScheme
 
+// ApplyToDoc applies the config to doc, and warns about undeclared/unused tags and undefined security schemes
 func (c OpenAPIConfig) ApplyToDoc(doc *spec.T) {
+	checkTags(doc, c.Tags)
+	c.applyToDoc(doc)
+	checkSecurity(doc)
+}
+
+// applyToDoc is ApplyToDoc without warnings. In 'documents' mode tag warnings are reported against the full document,
+// and security warnings are reported for each document.
+func (c OpenAPIConfig) applyToDoc(doc *spec.T) {
 	if c.OpenAPI != "" {
 		doc.OpenAPI = c.OpenAPI
 	}
 	if c.Info != nil {
-		if c.Info.Version != "" {
-			doc.Info.Version = c.Info.Version
-		}
-		if c.Info.Title != "" {
-			doc.Info.Title = c.Info.Title
-		}
-		if c.Info.Description != "" {
-			doc.Info.Description = c.Info.Description
+		mergeInfo(doc.Info, c.Info)
+	}
+	if len(c.Servers) > 0 {
+		doc.Servers = c.Servers
+	}
+	if c.ExternalDocs != nil {
+		doc.ExternalDocs = c.ExternalDocs
+	}
+	if c.Security != nil {
+		doc.Security = *c.Security
+	}
+	if len(c.Extensions) > 0 {
+		// documents filtered from the same document share the extensions
+		extensions := make(map[string]interface{}, len(doc.Extensions)+len(c.Extensions))
+		for key, value := range doc.Extensions {
+			extensions[key] = value
 		}
-		if c.Info.TermsOfService != "" {
-			doc.Info.TermsOfService = c.Info.TermsOfService
+		for key, value := range c.Extensions {
+			extensions[key] = value
 		}
+		doc.Extensions = extensions
 	}
 	if c.SecuritySchemes != nil {
 		doc.Components.SecuritySchemes = make(map[string]*spec.SecuritySchemeRef)
@@ -59,6 +116,48 @@ func (c OpenAPIConfig) ApplyToDoc(doc *spec.T) {
 			doc.Components.SecuritySchemes[name] = &spec.SecuritySchemeRef{Value: scheme}
 		}
 	}
+	c.applyTags(doc)
+}
+
+// mergeInfo overrides fields of dst with the non-empty fields of src
+func mergeInfo(dst, src *spec.Info) {
+	if src.Version != "" {
+		dst.Version = src.Version
+	}
+	if src.Title != "" {
+		dst.Title = src.Title
+	}
+	if src.Description != "" {
+		dst.Description = src.Description
+	}
+	if src.TermsOfService != "" {
+		dst.TermsOfService = src.TermsOfService
+	}
+	if src.Contact != nil {
+		dst.Contact = src.Contact
+	}
+	if src.License != nil {
+		dst.License = src.License
+	}
+}
+
+func (c OpenAPIConfig) validate() error {
+	for _, server := range c.Servers {
+		if server == nil {
+			continue
+		}
+		err := server.Validate(context.Background())
+		if err != nil {
+			return fmt.Errorf("invalid openapi.servers %q: %w", server.URL, err)
+		}
+	}
+	if c.Info != nil && c.Info.License != nil && c.Info.License.Name == "" {
+		return fmt.Errorf("invalid openapi.info.license: 'name' is required")
+	}
+	if c.ExternalDocs != nil && c.ExternalDocs.URL == "" {
+		return fmt.Errorf("invalid openapi.externalDocs: 'url' is required")
+	}
+	return nil
 }
 
 type GeneratorConfig struct {
@@ -118,7 +217,7 @@ func (e *Entrypoint) Run(args []string) {
 	app.Flags = append(app.Flags, &cli.StringFlag{
 		Name:        "output",
 		Aliases:     []string{"o"},
-		Usage:       "output directory of openapi.json",
+		Usage:       "output directory of the documentation",
 		Destination: &e.cfg.Output,
 	})
 	app.Flags = append(app.Flags, &cli.StringSliceFlag{
@@ -150,7 +249,7 @@ func (e *Entrypoint) Run(args []string) {
 		Destination: &e.cfg.LogLevel,
 	})
 
-	app.Commands = append(app.Commands, showVersion())
+	app.Commands = append(app.Commands, showVersion(), bundleCommand())
 
 	app.Action = e.run
 
@@ -178,6 +277,11 @@ func (e *Entrypoint) before(c *cli.Context) error {
 		if err != nil {
 			return err
 		}
+		e.cfg.OpenAPI.Extensions = extensionsOf(e.k.Get("openapi"))
+		err = e.cfg.OpenAPI.validate()
+		if err != nil {
+			return err
+		}
 	}
 
 	if e.cfg.Plugin == "" {
@@ -189,6 +293,30 @@ func (e *Entrypoint) before(c *cli.Context) error {
 	if e.cfg.Output == "" {
 		e.cfg.Output = "docs"
 	}
+	switch e.cfg.OutputFormat {
+	case "":
+		e.cfg.OutputFormat = OutputFormatJson
+	case OutputFormatJson, OutputFormatYaml, OutputFormatBoth:
+	default:
+		return fmt.Errorf("invalid outputFormat %q. available: %s, %s, %s", e.cfg.OutputFormat, OutputFormatJson, OutputFormatYaml, OutputFormatBoth)
+	}
+	if e.cfg.OutputFile == "" {
+		e.cfg.OutputFile = "openapi"
+	}
+	if e.cfg.Split != nil {
+		err := e.cfg.Split.validate()
+		if err != nil {
+			return err
+		}
+	}
+	err := e.validateDocuments()
+	if err != nil {
+		return err
+	}
+	err = e.validateVariants()
+	if err != nil {
+		return err
+	}
 
 	// Initialize global logger
 	logLevel := ParseLogLevel(e.cfg.LogLevel)
@@ -198,8 +326,181 @@ func (e *Entrypoint) before(c *cli.Context) error {
 	return nil
 }
 
+const (
+	OutputFormatJson = "json"
+	OutputFormatYaml = "yaml"
+	OutputFormatBoth = "both"
+)
+
+// writeDoc writes documentation into output directory in configured format. name is the file name of the
+// documentation, and swaggerName is the file name of the Swagger 2.0 documentation
+func (e *Entrypoint) writeDoc(doc *spec.T, a *Analyzer, name, swaggerName string) error {
+	var content interface{} = doc
+	if doc.IsOpenAPI31() {
+		doc31, err := doc.To31()
+		if err != nil {
+			return fmt.Errorf("convert to OpenAPI 3.1 err: %s", err.Error())
+		}
+		content = doc31
+	}
+	var err error
+	if e.cfg.Split != nil {
+		err = e.writeSplitDoc(name, content, e.cfg.Split.grouper(a))
+	} else {
+		err = e.writeFile(name, content)
+	}
+	if err != nil {
+		return err
+	}
+
+	if e.cfg.Swagger2 {
+		swagger, warnings, err := doc.ToSwagger2()
+		if err != nil {
+			return fmt.Errorf("convert to Swagger 2.0 err: %s", err.Error())
+		}
+		for _, warning := range warnings {
+			LogWarn("[Swagger 2.0]: %s", warning)
+		}
+		err = e.writeFile(swaggerName, swagger)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile writes content into output directory in configured format. Extension of name is appended by format
+func (e *Entrypoint) writeFile(name string, content interface{}) error {
+	name, exts := e.outputFiles(name)
+	err := os.MkdirAll(filepath.Join(e.cfg.Output, filepath.Dir(name)), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	for _, ext := range exts {
+		docContent, err := encodeDoc(ext, content)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(e.cfg.Output, name+ext), docContent, fs.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputFiles returns name without extension and the extensions of files in configured format
+func (e *Entrypoint) outputFiles(name string) (string, []string) {
+	ext := filepath.Ext(name)
+	switch ext {
+	case ".json", ".yaml", ".yml":
+		name = strings.TrimSuffix(name, ext)
+	default:
+		ext = ""
+	}
+
+	var exts []string
+	if e.cfg.OutputFormat == OutputFormatJson || e.cfg.OutputFormat == OutputFormatBoth {
+		exts = append(exts, ".json")
+	}
+	if e.cfg.OutputFormat == OutputFormatYaml || e.cfg.OutputFormat == OutputFormatBoth {
+		if ext != ".yml" {
+			ext = ".yaml"
+		}
+		exts = append(exts, ext)
+	}
+	return name, exts
+}
+
+// encodeDoc encodes content as JSON or YAML according to the file extension
+func encodeDoc(ext string, content interface{}) ([]byte, error) {
+	if ext == ".yaml" || ext == ".yml" {
+		docContent, err := spec.ToYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("yaml marshal err: %s", err.Error())
+		}
+		return docContent, nil
+	}
+	docContent, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("json MarshalIndent err: %s", err.Error())
+	}
+	return docContent, nil
+}
+
+// loadConfig loads configuration file. Environment variables in string values are expanded
 func (e *Entrypoint) loadConfig(cfg string) error {
-	return e.k.Load(file.Provider(cfg), yaml.Parser())
+	content, err := file.Provider(cfg).ReadBytes()
+	if err != nil {
+		return err
+	}
+	values, err := yaml.Parser().Unmarshal(content)
+	if err != nil {
+		return err
+	}
+	expanded, err := expandEnv(values)
+	if err != nil {
+		return fmt.Errorf("load config %s: %w", cfg, err)
+	}
+	return e.k.Load(confmap.Provider(expanded.(map[string]interface{}), ""), nil)
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?}`)
+
+// expandEnv replaces "${NAME}" and "${NAME:-default}" in string values with environment variables.
+// Returns error if the variable is not set and has no default value.
+func expandEnv(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		var err error
+		res := envPattern.ReplaceAllStringFunc(value, func(s string) string {
+			match := envPattern.FindStringSubmatch(s)
+			if env, ok := os.LookupEnv(match[1]); ok {
+				return env
+			}
+			if match[2] == "" && err == nil {
+				err = fmt.Errorf("environment variable %s is not set", match[1])
+			}
+			return match[3]
+		})
+		return res, err
+	case map[string]interface{}:
+		for key, item := range value {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = expanded
+		}
+	case []interface{}:
+		for i, item := range value {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = expanded
+		}
+	}
+	return value, nil
+}
+
+// extensionsOf returns specification extensions (keys starting with "x-") of the config section
+func extensionsOf(section interface{}) map[string]interface{} {
+	values, ok := section.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var res map[string]interface{}
+	for key, value := range values {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]interface{})
+		}
+		res[key] = value
+	}
+	return res
 }
 
 func (e *Entrypoint) run(c *cli.Context) error {
@@ -244,20 +545,81 @@ func (e *Entrypoint) run(c *cli.Context) error {
 	processedAnalyzer := a.Process(e.cfg.Dir)
 	LogDebug("doc0.2: Process处理完成")
 
+	err = e.cfg.OpenAPI.buildWebhooks(processedAnalyzer)
+	if err != nil {
+		return err
+	}
 	rawDoc := processedAnalyzer.Doc()
 	LogDebug("doc0.3: 获取原始文档完成，开始Specialize处理")
 
 	// 执行Specialize，这里可能出现unknown type error
 	doc := rawDoc.Specialize()
 	LogDebug("doc1: Specialize处理完成")
-	e.cfg.OpenAPI.ApplyToDoc(doc)
+	// 参数按位置和名称排序，保证多次生成的文档内容一致
+	doc.SortParameters()
+	if e.cfg.SourceLocation {
+		processedAnalyzer.AddSourceExtensions(doc)
+	}
+	// 在命名之前合并, 合并后的泛型实例(如 Resp[any])同样按照命名规则命名
+	if e.cfg.DedupeSchemas {
+		merged := doc.DeduplicateSchemas()
+		for _, key := range utils.SortedKeys(merged) {
+			LogInfo("merged schema %s into %s", key, merged[key])
+		}
+	}
+	if e.cfg.SchemaNaming != nil {
+		namer, err := newSchemaNamer(e.cfg.SchemaNaming, processedAnalyzer.definitions)
+		if err != nil {
+			return err
+		}
+		err = namer.apply(doc)
+		if err != nil {
+			return err
+		}
+	}
+	if e.cfg.SplitSchemasByDirection {
+		doc.SplitSchemasByDirection()
+	}
+	if e.cfg.PruneSchemas {
+		removed := doc.PruneSchemas()
+		if len(removed) > 0 {
+			LogInfo("removed %d unused schemas: %s", len(removed), strings.Join(removed, ", "))
+		}
+	}
+	if len(e.cfg.Documents) == 0 {
+		e.cfg.OpenAPI.ApplyToDoc(doc)
+	} else {
+		// 每份文档只包含部分接口, Tag 相关的警告针对完整文档只输出一次.
+		// 完整文档同样应用顶层配置, 各文档基于它生成, 代码生成器也使用它
+		checkTags(doc, e.cfg.documentTags())
+		e.cfg.OpenAPI.applyToDoc(doc)
+	}
+	// 应用 overlays，输出的文档及代码生成器均使用应用后的文档.
+	// 配置了 documents 时, 各文档基于应用 overlays 之前的完整文档生成, 并在应用各自的配置之后再分别应用 overlays
+	patched, err := e.applyOverlays(doc)
+	if err != nil {
+		return err
+	}
 	// write documentation
-	{
-		docContent, err := json.MarshalIndent(doc, "", "    ")
+	if len(e.cfg.Documents) == 0 {
+		err = e.writeDocWithVariants(patched, processedAnalyzer, e.cfg.OutputFile, "swagger")
+		if err != nil {
+			return err
+		}
+	}
+	for i, item := range e.cfg.Documents {
+		document, err := item.build(doc, processedAnalyzer, e.cfg.Dir)
+		if err != nil {
+			return fmt.Errorf("invalid documents[%d]: %w", i, err)
+		}
+		LogInfo("document %s: %d paths", item.OutputFile, len(document.Paths))
+		e.cfg.OpenAPI.override(item.OpenAPI).applyToDoc(document)
+		checkSecurity(document)
+		document, err = e.applyOverlays(document)
 		if err != nil {
-			return fmt.Errorf("json MarshalIndent err: %s", err.Error())
+			return fmt.Errorf("document %s: %w", item.OutputFile, err)
 		}
-		err = os.WriteFile(filepath.Join(e.cfg.Output, "openapi.json"), docContent, fs.ModePerm)
+		err = e.writeDocWithVariants(document, processedAnalyzer, item.OutputFile, item.OutputFile+".swagger")
 		if err != nil {
 			return err
 		}
@@ -267,7 +629,7 @@ func (e *Entrypoint) run(c *cli.Context) error {
 	for idx, item := range e.cfg.Generators {
 		err = newGeneratorExecutor(
 			item,
-			doc,
+			patched,
 			func(key string) interface{} {
 				confMap := e.k.Get("generators").([]interface{})[idx].(map[string]interface{})
 				val, ok := confMap[key]
@@ -0,0 +1,78 @@
+package eapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("EAPI_TEST_HOST", "api.example.com")
+	t.Setenv("EAPI_TEST_EMPTY", "")
+
+	values := map[string]interface{}{
+		"url":      "https://${EAPI_TEST_HOST}/v1",
+		"empty":    "[${EAPI_TEST_EMPTY:-unused}]",
+		"fallback": "${EAPI_TEST_MISSING:-http://localhost}",
+		"servers": []interface{}{
+			map[string]interface{}{"url": "${EAPI_TEST_HOST}"},
+			"${EAPI_TEST_MISSING:-}",
+		},
+		"port": 8080,
+		"text": "$EAPI_TEST_HOST and ${ not a variable }",
+	}
+	res, err := expandEnv(values)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"url":      "https://api.example.com/v1",
+		"empty":    "[]",
+		"fallback": "http://localhost",
+		"servers": []interface{}{
+			map[string]interface{}{"url": "api.example.com"},
+			"",
+		},
+		"port": 8080,
+		"text": "$EAPI_TEST_HOST and ${ not a variable }",
+	}, res)
+}
+
+func TestExpandEnv_Missing(t *testing.T) {
+	_, err := expandEnv([]interface{}{"${EAPI_TEST_HOST:-localhost}", "${EAPI_TEST_MISSING}"})
+	assert.EqualError(t, err, "environment variable EAPI_TEST_MISSING is not set")
+}
+
+func TestEntrypoint_loadConfig(t *testing.T) {
+	t.Setenv("EAPI_TEST_TITLE", "Shop")
+	file := filepath.Join(t.TempDir(), "eapi.yaml")
+	err := os.WriteFile(file, []byte("plugin: gin\nopenapi:\n  info:\n    title: ${EAPI_TEST_TITLE}\n    version: ${EAPI_TEST_VERSION:-1.0.0}\n"), 0644)
+	require.NoError(t, err)
+
+	e := NewEntrypoint()
+	require.NoError(t, e.loadConfig(file))
+	assert.Equal(t, "Shop", e.k.String("openapi.info.title"))
+	assert.Equal(t, "1.0.0", e.k.String("openapi.info.version"))
+
+	err = os.WriteFile(file, []byte("plugin: gin\noutput: ${EAPI_TEST_MISSING}\n"), 0644)
+	require.NoError(t, err)
+	err = NewEntrypoint().loadConfig(file)
+	assert.EqualError(t, err, "load config "+file+": environment variable EAPI_TEST_MISSING is not set")
+}
+
+func TestExtensionsOf(t *testing.T) {
+	res := extensionsOf(map[string]interface{}{
+		"info":       map[string]interface{}{"title": "Shop"},
+		"x-logo":     map[string]interface{}{"url": "logo.png"},
+		"x-internal": true,
+	})
+	assert.Equal(t, map[string]interface{}{
+		"x-logo":     map[string]interface{}{"url": "logo.png"},
+		"x-internal": true,
+	}, res)
+
+	assert.Nil(t, extensionsOf(map[string]interface{}{"info": nil}))
+	assert.Nil(t, extensionsOf(nil))
+	assert.Nil(t, extensionsOf("x-logo"))
+}
@@ -85,3 +85,23 @@ func (e *CommentStack) LookupTags() []string {
 		return t.(*annotation.TagAnnotation).Tag
 	})
 }
+
+// Comments returns comments of the stack from the innermost to the outermost
+func (e *CommentStack) Comments() []*Comment {
+	var res []*Comment
+	for stack := e; stack != nil; stack = stack.parent {
+		if stack.comment != nil {
+			res = append(res, stack.comment)
+		}
+	}
+	return res
+}
+
+// LookupPrefix returns path prefix declared by the innermost @prefix annotation
+func (e *CommentStack) LookupPrefix() string {
+	annotations := e.LookupAnnotations(annotation.Prefix)
+	if len(annotations) == 0 {
+		return ""
+	}
+	return annotations[len(annotations)-1].(*annotation.PrefixAnnotation).Path
+}
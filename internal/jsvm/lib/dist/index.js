@@ -420,6 +420,10 @@ class Printer {
       return typeName || 'unknown';
     }
 
+    if (schema.oneOf?.length) {
+      return this.printUnionType(schema.oneOf)
+    }
+
     const ext = schema.ext;
     switch (ext?.type) {
       case "any":
@@ -531,6 +535,13 @@ class Printer {
     ]);
   }
 
+  /**
+   * @param {Schema[]} schemas
+   */
+  printUnionType(schemas) {
+    return group(join([" |", line], schemas.map(t => this.typeName(t))))
+  }
+
   printSpecificType(ext) {
     return [
       this.typeName(ext.specificType.type), '<', join(', ', ext.specificType.args.map(t => this.typeName(t))), '>',
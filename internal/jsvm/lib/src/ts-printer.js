@@ -1,4 +1,4 @@
-import {group, hardline, indent, join} from "./doc-builders"
+import {group, hardline, indent, join, line} from "./doc-builders"
 
 const SCHEMAS_REF_PREFIX = '#/components/schemas/';
 
@@ -88,6 +88,10 @@ class Printer {
       return typeName || 'unknown';
     }
 
+    if (schema.oneOf?.length) {
+      return this.printUnionType(schema.oneOf)
+    }
+
     const ext = schema.ext;
     switch (ext?.type) {
       case "any":
@@ -199,6 +203,13 @@ class Printer {
     ]);
   }
 
+  /**
+   * @param {Schema[]} schemas
+   */
+  printUnionType(schemas) {
+    return group(join([" |", line], schemas.map(t => this.typeName(t))))
+  }
+
   printSpecificType(ext) {
     return [
       this.typeName(ext.specificType.type), '<', join(', ', ext.specificType.args.map(t => this.typeName(t))), '>',
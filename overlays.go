@@ -0,0 +1,62 @@
+package eapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chenwei67/eapi/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// loadOverlay loads OpenAPI Overlay document or JSON Merge Patch from file (JSON or YAML). Returns *spec.Overlay for
+// overlay documents (which have 'overlay' field), or the patch
+func loadOverlay(file string) (interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	// JSON is a subset of YAML
+	err = yaml.Unmarshal(data, &value)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value, nil
+	}
+	if _, ok := object["overlay"]; !ok {
+		return value, nil
+	}
+	data, err = json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	var overlay spec.Overlay
+	err = json.Unmarshal(data, &overlay)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	return &overlay, nil
+}
+
+// applyOverlays applies the overlays and merge patches in order and returns the patched copy of doc
+func (e *Entrypoint) applyOverlays(doc *spec.T) (*spec.T, error) {
+	for _, file := range e.cfg.Overlays {
+		overlay, err := loadOverlay(file)
+		if err != nil {
+			return nil, fmt.Errorf("load overlay %s: %w", file, err)
+		}
+		if o, ok := overlay.(*spec.Overlay); ok {
+			doc, err = doc.ApplyOverlay(o)
+		} else {
+			doc, err = doc.ApplyMergePatch(overlay)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("apply overlay %s: %w", file, err)
+		}
+		LogInfo("applied overlay %s", file)
+	}
+	return doc, nil
+}
@@ -84,11 +84,15 @@ func (p *ParamParser) parseField(name *ast.Ident, field *ast.Field) (param *spec
 	}
 
 	// parse comments
-	comments := p.ctx.ParseComment(field.Doc)
+	comments := p.ctx.ParseComment(commentGroupOfField(p.ctx, field))
+	comments.ReportUnknownAnnotations(p.ctx, fieldOwnerOf(field))
 	if comments != nil {
 		param.Required = comments.Required()
 		param.Description = comments.Text()
 		param.Deprecated = comments.Deprecated()
+		if comments.Internal() {
+			markInternal(&param.ExtensionProps)
+		}
 	}
 
 	return
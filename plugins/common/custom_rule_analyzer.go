@@ -111,7 +111,7 @@ func (p *CustomRuleAnalyzer) MatchCustomRequestRule(node ast.Node) (matched bool
 						reqBody.Description = comment.Text()
 					}
 					reqBody.WithSchemaRef(schema, []string{contentType})
-					p.spec.RequestBody = reqBody
+					p.spec.SetRequestBody(reqBody)
 				}
 
 			},
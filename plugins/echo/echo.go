@@ -168,7 +168,7 @@ func (p *Plugin) parseAPI(ctx *eapi.Context, callExpr *ast.CallExpr, comment *ea
 	method := selExpr.Sel.Name
 	api = eapi.NewAPI(method, fullPath)
 	api.Spec.LoadFromComment(ctx, comment)
-	api.Spec.LoadFromFuncDecl(ctx, handlerFnDef.Decl)
+	api.Spec.LoadFromFuncDecl(ctx.NewEnv().WithPackage(handlerFnDef.Pkg()).WithFile(handlerFnDef.File()), handlerFnDef.Decl)
 	if api.Spec.OperationID == "" {
 		id := comment.ID()
 		if id == "" {
@@ -115,7 +115,7 @@ func (p *handlerAnalyzer) parseBinding(call *ast.CallExpr) {
 			schema.Description = comment.Text()
 		}
 		reqBody := spec.NewRequestBody().WithSchemaRef(schema, []string{contentType})
-		p.spec.RequestBody = reqBody
+		p.spec.SetRequestBody(reqBody)
 	}
 }
 
@@ -184,7 +184,7 @@ func (p *handlerAnalyzer) parseFormData(call *ast.CallExpr, fieldType string, op
 	requestBody := p.spec.RequestBody
 	if requestBody == nil {
 		requestBody = spec.NewRequestBody().WithContent(spec.NewContent())
-		p.spec.RequestBody = requestBody
+		p.spec.SetRequestBody(requestBody)
 	}
 	mediaType := requestBody.GetMediaType(eapi.MimeTypeFormData)
 	if mediaType == nil {
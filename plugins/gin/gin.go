@@ -192,7 +192,7 @@ func (e *Plugin) parseAPI(ctx *analyzer.Context, callExpr *ast.CallExpr, comment
 	method := selExpr.Sel.Name
 	api = analyzer.NewAPI(method, fullPath)
 	api.Spec.LoadFromComment(ctx, comment)
-	api.Spec.LoadFromFuncDecl(ctx, handlerFnDef.Decl)
+	api.Spec.LoadFromFuncDecl(ctx.NewEnv().WithPackage(handlerFnDef.Pkg()).WithFile(handlerFnDef.File()), handlerFnDef.Decl)
 	if api.Spec.OperationID == "" {
 		id := comment.ID()
 		if id == "" {
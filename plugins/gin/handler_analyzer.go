@@ -11,8 +11,8 @@ import (
 	"github.com/chenwei67/eapi/plugins/common"
 	"github.com/chenwei67/eapi/spec"
 	"github.com/chenwei67/eapi/tag"
+	"github.com/chenwei67/eapi/utils"
 	"github.com/iancoleman/strcase"
-	"github.com/samber/lo"
 )
 
 const ginContextIdentName = "*github.com/gin-gonic/gin.Context"
@@ -185,7 +185,7 @@ func (p *handlerAnalyzer) parseBindWithContentType(call *ast.CallExpr, contentTy
 		schema.Description = comment.Text()
 	}
 	reqBody := spec.NewRequestBody().WithSchemaRef(schema, []string{contentType})
-	p.spec.RequestBody = reqBody
+	p.spec.SetRequestBody(reqBody)
 }
 
 func (p *handlerAnalyzer) parseResBody(call *ast.CallExpr, contentType string) {
@@ -255,7 +255,7 @@ func (p *handlerAnalyzer) parseFormData(call *ast.CallExpr, fieldType string, op
 	requestBody := p.spec.RequestBody
 	if requestBody == nil {
 		requestBody = spec.NewRequestBody().WithContent(spec.NewContent())
-		p.spec.RequestBody = requestBody
+		p.spec.SetRequestBody(requestBody)
 	}
 	mediaType := requestBody.GetMediaType(analyzer.MimeTypeFormData)
 	if mediaType == nil {
@@ -364,12 +364,15 @@ func (p *handlerAnalyzer) parseBindUri(call *ast.CallExpr) {
 	if schema == nil {
 		return
 	}
-	for name, property := range schema.Properties {
-		p.api.Spec.Parameters = lo.Filter(p.api.Spec.Parameters, func(ref *spec.ParameterRef, i int) bool { return ref.Name != name })
-		param := spec.NewPathParameter(name).WithSchema(property)
-		param.Description = property.Description
-		p.api.Spec.AddParameter(param)
-	}
+	utils.RangeMapInOrder(
+		schema.Properties,
+		func(a, b string) bool { return a < b },
+		func(name string, property *spec.SchemaRef) {
+			param := spec.NewPathParameter(name).WithSchema(property)
+			param.Description = property.Description
+			p.api.Spec.SetParameter(param)
+		},
+	)
 }
 
 // parseBindQuery 处理 ShouldBindQuery 和 BindQuery 方法
@@ -392,24 +395,26 @@ func (p *handlerAnalyzer) parseBindQuery(call *ast.CallExpr) {
 	}
 	
 	// 将结构体的每个字段转换为查询参数
-	for name, property := range schema.Properties {
-		// 移除同名的现有参数
-		p.api.Spec.Parameters = lo.Filter(p.api.Spec.Parameters, func(ref *spec.ParameterRef, i int) bool { return ref.Name != name })
-		
-		// 创建查询参数
-		param := spec.NewQueryParameter(name).WithSchema(property)
-		param.Description = property.Description
-		
-		// 检查是否为必需参数（从schema的required字段中获取）
-		for _, requiredField := range schema.Required {
-			if requiredField == name {
-				param.Required = true
-				break
+	utils.RangeMapInOrder(
+		schema.Properties,
+		func(a, b string) bool { return a < b },
+		func(name string, property *spec.SchemaRef) {
+			// 创建查询参数
+			param := spec.NewQueryParameter(name).WithSchema(property)
+			param.Description = property.Description
+
+			// 检查是否为必需参数（从schema的required字段中获取）
+			for _, requiredField := range schema.Required {
+				if requiredField == name {
+					param.Required = true
+					break
+				}
 			}
-		}
-		
-		p.api.Spec.AddParameter(param)
-	}
+
+			// 替换同名的现有参数
+			p.api.Spec.SetParameter(param)
+		},
+	)
 }
 
 func (p *handlerAnalyzer) parseUriFieldName(name string, field *ast.Field) string {
@@ -483,7 +488,7 @@ func (p *handlerAnalyzer) parseBindWith(call *ast.CallExpr) {
 		schema.Description = comment.Text()
 	}
 	reqBody := spec.NewRequestBody().WithSchemaRef(schema, []string{contentType})
-	p.spec.RequestBody = reqBody
+	p.spec.SetRequestBody(reqBody)
 }
 
 // getContentTypeFromBinding 根据绑定类型推断内容类型
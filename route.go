@@ -1,12 +1,15 @@
 package eapi
 
 import (
+	"fmt"
 	"go/ast"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/chenwei67/eapi/annotation"
 	"github.com/chenwei67/eapi/spec"
+	"github.com/samber/lo"
 )
 
 type RouteGroup struct {
@@ -17,13 +20,16 @@ type API struct {
 	Method   string
 	FullPath string
 	Spec     *APISpec
+
+	// import path of the package which registers the route
+	pkg string
 }
 
 func NewAPI(method string, fullPath string) *API {
 	return &API{
 		Method:   method,
 		FullPath: fullPath,
-		Spec:     NewAPISpec(),
+		Spec:     NewAPISpec().withOwner(method + " " + fullPath),
 	}
 }
 
@@ -61,6 +67,23 @@ func (r *APIs) add(items ...*API) {
 type APISpec struct {
 	Consumes []string
 	*spec.Operation
+
+	// parameters declared by @param annotations. key: "in:name"
+	declaredParams map[string]struct{}
+	// status codes of responses declared by @response annotations
+	declaredResponses map[string]struct{}
+	// whether request body is declared by @request annotation
+	declaredRequestBody bool
+	// description of the operation in diagnostics. e.g. "GET /users"
+	owner string
+	// import path of the package which declares the handler
+	pkg string
+	// location of the handler function
+	source *spec.SourceLocation
+	// fields which are inherited from comments of enclosing scopes and can be overridden
+	inherited struct {
+		tags, security, servers bool
+	}
 }
 
 func NewAPISpec() *APISpec {
@@ -73,8 +96,22 @@ func NewAPISpec() *APISpec {
 	}
 }
 
+func (s *APISpec) withOwner(owner string) *APISpec {
+	s.owner = owner
+	return s
+}
+
 // LoadFromFuncDecl load annotations/description from comments of handler function
 func (s *APISpec) LoadFromFuncDecl(ctx *Context, funcDecl *ast.FuncDecl) {
+	if s.pkg == "" {
+		s.pkg = ctx.Package().PkgPath
+	}
+	if s.source == nil {
+		s.source = sourceLocationOf(ctx.Package(), funcDecl.Pos())
+		if s.source != nil {
+			s.source.Func = funcNameOf(ctx.Package(), funcDecl)
+		}
+	}
 	cg := funcDecl.Doc
 	comment := ParseComment(cg, ctx.Package().Fset)
 	s.LoadFromComment(ctx, comment)
@@ -86,14 +123,17 @@ func (s *APISpec) LoadFromFuncDecl(ctx *Context, funcDecl *ast.FuncDecl) {
 
 func (s *APISpec) LoadFromComment(ctx *Context, comment *Comment) {
 	if comment != nil {
+		comment.ReportUnknownAnnotations(ctx, strings.TrimSpace("operation "+s.owner))
+		declareTags(ctx.Doc(), comment)
 		if s.Description == "" {
 			s.Description = comment.Text()
 		}
 		if s.Summary == "" {
 			s.Summary = comment.Summary()
 		}
-		if len(s.Tags) == 0 {
-			s.Tags = comment.Tags()
+		if tags := comment.Tags(); len(tags) > 0 && (len(s.Tags) == 0 || s.inherited.tags) {
+			s.Tags = tags
+			s.inherited.tags = false
 		}
 		if s.OperationID == "" {
 			s.OperationID = comment.ID()
@@ -102,16 +142,275 @@ func (s *APISpec) LoadFromComment(ctx *Context, comment *Comment) {
 			s.Consumes = append(s.Consumes, comment.Consumes()...)
 		}
 		if !s.Deprecated {
-			comment.Deprecated()
+			s.Deprecated = comment.Deprecated()
+		}
+		if security := comment.Security(); security != nil && (s.Security == nil || s.inherited.security) {
+			s.Security = security
+			s.inherited.security = false
+		}
+		if servers := comment.Servers(); len(servers) > 0 && (s.Servers == nil || s.inherited.servers) {
+			s.Servers = &servers
+			s.inherited.servers = false
+		}
+		comment.ApplyExtensions(&s.ExtensionProps)
+		if comment.Internal() {
+			markInternal(&s.ExtensionProps)
 		}
-		if s.Security == nil {
-			s.Security = comment.Security()
+		for _, param := range comment.Params() {
+			s.declareParameter(newParameterFromAnnotation(param))
 		}
+		for _, response := range comment.Responses() {
+			s.declareResponse(ctx, comment, response)
+		}
+		if request := comment.Request(); request != nil {
+			s.declareRequestBody(ctx, comment, request)
+		}
+		for _, callback := range comment.Callbacks() {
+			s.declareCallback(ctx, comment, callback)
+		}
+	}
+	s.inherit(ctx)
+}
+
+// inherit applies defaults declared in comments of enclosing blocks, functions, files and packages.
+// Values declared in the innermost comment take precedence.
+func (s *APISpec) inherit(ctx *Context) {
+	stack := ctx.CommentStack()
+	if stack == nil {
+		return
 	}
 	if len(s.Tags) == 0 {
-		s.Tags = ctx.CommentStack().LookupTags()
+		s.Tags = stack.LookupTags()
+		s.inherited.tags = len(s.Tags) > 0
 	}
 	if s.Security == nil {
-		s.Security = convertSecAnnotationToSecurityRequirements(ctx.CommentStack().LookupAnnotations(annotation.Security))
+		s.Security = convertSecAnnotationToSecurityRequirements(stack.LookupAnnotations(annotation.Security))
+		s.inherited.security = s.Security != nil
+	}
+	if s.Servers == nil {
+		if servers := convertServerAnnotations(stack.LookupAnnotations(annotation.Server)); len(servers) > 0 {
+			s.Servers = &servers
+			s.inherited.servers = true
+		}
+	}
+	if !s.Deprecated && stack.ResolveByAnnotation(annotation.Deprecated) != nil {
+		s.Deprecated = true
+	}
+	if stack.ResolveByAnnotation(annotation.Internal) != nil {
+		markInternal(&s.ExtensionProps)
+	}
+	for _, comment := range stack.Comments() {
+		for _, response := range comment.Responses() {
+			if _, ok := s.Responses[response.Code]; ok {
+				continue // declared by inner comments or detected from handler
+			}
+			if res := s.responseOf(ctx, comment, response); res != nil {
+				s.addResponse(response.Code, res)
+			}
+		}
+	}
+}
+
+// SetParameter adds the parameter, replacing the existing one with the same name.
+// Parameters declared by @param annotations take precedence and will not be replaced.
+func (s *APISpec) SetParameter(param *spec.Parameter) {
+	for _, item := range s.Parameters {
+		if item.Name == param.Name && s.isDeclaredParameter(item) {
+			return
+		}
+	}
+	s.Parameters = lo.Filter(s.Parameters, func(item *spec.ParameterRef, i int) bool { return item.Name != param.Name })
+	s.AddParameter(param)
+}
+
+// AddResponse sets response of the status code. Responses declared by @response annotations take precedence.
+func (s *APISpec) AddResponse(status int, response *spec.Response) {
+	code := "default"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	if _, ok := s.declaredResponses[code]; ok {
+		return
+	}
+	s.Operation.AddResponse(status, response)
+}
+
+// SetRequestBody sets request body unless it is declared by @request annotation
+func (s *APISpec) SetRequestBody(requestBody *spec.RequestBody) {
+	if s.declaredRequestBody {
+		return
+	}
+	s.RequestBody = requestBody
+}
+
+func (s *APISpec) declareResponse(ctx *Context, comment *Comment, annot *annotation.ResponseAnnotation) {
+	if _, ok := s.declaredResponses[annot.Code]; ok {
+		return // the first declaration wins
+	}
+	response := s.responseOf(ctx, comment, annot)
+	if response == nil {
+		return
+	}
+	if s.declaredResponses == nil {
+		s.declaredResponses = make(map[string]struct{})
+	}
+	s.declaredResponses[annot.Code] = struct{}{}
+	s.addResponse(annot.Code, response)
+}
+
+// responseOf builds response from @response annotation. Returns nil if the type can not be resolved.
+func (s *APISpec) responseOf(ctx *Context, comment *Comment, annot *annotation.ResponseAnnotation) *spec.Response {
+	response := spec.NewResponse()
+	description := annot.Description
+	if description == "" {
+		code, _ := strconv.Atoi(annot.Code)
+		description = http.StatusText(code)
+	}
+	if description == "" {
+		// description is required by OpenAPI, and there is no standard text for "default"
+		description = "Default response"
+	}
+	response.WithDescription(description)
+	if annot.DataType != "" {
+		schema, err := schemaOfAnnotationType(ctx, annot.DataType)
+		if err != nil {
+			comment.ReportError(ctx, annot, annotation.NewParseError(annot.TypeColumn, err.Error()))
+			return nil
+		}
+		contentTypes := comment.Produces()
+		if len(contentTypes) == 0 {
+			contentTypes = []string{MimeTypeJson}
+		}
+		response.WithContent(spec.NewContentWithSchemaRef(schema, contentTypes))
+	}
+	return response
+}
+
+func (s *APISpec) addResponse(code string, response *spec.Response) {
+	if s.Responses == nil {
+		s.Responses = spec.NewResponses()
+	}
+	s.Responses[code] = response
+}
+
+func (s *APISpec) declareRequestBody(ctx *Context, comment *Comment, annot *annotation.RequestAnnotation) {
+	if s.declaredRequestBody {
+		return // the first declaration wins
+	}
+	schema, err := schemaOfAnnotationType(ctx, annot.DataType)
+	if err != nil {
+		comment.ReportError(ctx, annot, annotation.NewParseError(annot.TypeColumn, err.Error()))
+		return
+	}
+	contentType := annot.ContentType
+	if contentType == "" && len(s.Consumes) > 0 {
+		contentType = s.Consumes[0]
+	}
+	if contentType == "" {
+		contentType = MimeTypeJson
+	}
+	s.declaredRequestBody = true
+	s.RequestBody = spec.NewRequestBody().WithSchemaRef(schema, []string{contentType})
+}
+
+// declareCallback adds the operation declared by @callback annotation
+func (s *APISpec) declareCallback(ctx *Context, comment *Comment, annot *annotation.CallbackAnnotation) {
+	callback, ok := s.Callbacks[annot.Name]
+	if !ok || callback.Value == nil {
+		callback = &spec.CallbackRef{Value: &spec.Callback{}}
+	}
+	pathItem := (*callback.Value)[annot.Expression]
+	if pathItem == nil {
+		pathItem = &spec.PathItem{}
+	}
+	if pathItem.GetOperation(annot.Method) != nil {
+		return // the first declaration wins
+	}
+
+	operation := spec.NewOperation()
+	operation.Summary = annot.Description
+	if annot.DataType != "" {
+		schema, err := schemaOfAnnotationType(ctx, annot.DataType)
+		if err != nil {
+			comment.ReportError(ctx, annot, annotation.NewParseError(annot.TypeColumn, err.Error()))
+			return
+		}
+		operation.RequestBody = spec.NewRequestBody().WithSchemaRef(schema, []string{MimeTypeJson})
+	}
+	operation.Responses = spec.Responses{"200": spec.NewResponse().WithDescription(http.StatusText(http.StatusOK))}
+	pathItem.SetOperation(annot.Method, operation)
+
+	(*callback.Value)[annot.Expression] = pathItem
+	if s.Callbacks == nil {
+		s.Callbacks = make(spec.Callbacks)
+	}
+	s.Callbacks[annot.Name] = callback
+}
+
+// schemaOfAnnotationType resolves type expression written in annotations in the scope of current file
+func schemaOfAnnotationType(ctx *Context, typeExpr string) (*spec.SchemaRef, error) {
+	t, err := ctx.ParseTypeExpr(typeExpr)
+	if err != nil {
+		return nil, err
+	}
+	builder := NewSchemaBuilder(ctx, "")
+	if !builder.resolvable(t) {
+		return nil, fmt.Errorf("unresolved type '%s'. make sure the package is in the module or 'depends'", typeExpr)
+	}
+	return builder.parseType(t), nil
+}
+
+func (s *APISpec) declareParameter(param *spec.Parameter) {
+	if s.declaredParams == nil {
+		s.declaredParams = make(map[string]struct{})
+	}
+	if s.isDeclaredParameter(param) {
+		return // the first declaration wins
+	}
+	s.declaredParams[param.In+":"+param.Name] = struct{}{}
+	s.Parameters = lo.Filter(s.Parameters, func(item *spec.ParameterRef, i int) bool {
+		return item.Name != param.Name || item.In != param.In
+	})
+	s.AddParameter(param)
+}
+
+func (s *APISpec) isDeclaredParameter(param *spec.Parameter) bool {
+	_, ok := s.declaredParams[param.In+":"+param.Name]
+	return ok
+}
+
+func newParameterFromAnnotation(annot *annotation.ParamAnnotation) *spec.Parameter {
+	param := &spec.Parameter{
+		Name:        annot.Name,
+		In:          annot.In,
+		Required:    annot.Required || annot.In == spec.ParameterInPath,
+		Description: annot.Description,
+	}
+	elemType, isArray := strings.CutPrefix(annot.DataType, "[]")
+	schema := paramSchemaOf(elemType)
+	if isArray {
+		schema = spec.NewArraySchema(schema)
+	}
+	return param.WithSchema(schema)
+}
+
+func paramSchemaOf(dataType string) *spec.Schema {
+	switch dataType {
+	case "integer", "int":
+		return spec.NewIntegerSchema()
+	case "int32":
+		return spec.NewInt32Schema()
+	case "int64":
+		return spec.NewInt64Schema()
+	case "number", "float", "float64":
+		return spec.NewFloat64Schema()
+	case "float32":
+		return spec.NewFloat64Schema().WithFormat("float")
+	case "boolean", "bool":
+		return spec.NewBoolSchema()
+	case "file":
+		return spec.NewStringSchema().WithFormat("binary")
+	default:
+		return spec.NewStringSchema()
 	}
 }
@@ -55,7 +55,7 @@ func (s *SchemaBuilder) clone() *SchemaBuilder {
 }
 
 func (s *SchemaBuilder) parseTypeDef(def *TypeDefinition) *spec.SchemaRef {
-	schemaRef := s.parseTypeSpec(def.Spec)
+	schemaRef := s.parseTypeSpec(def.Spec, def.CommentGroup())
 	if schemaRef == nil {
 		return nil
 	}
@@ -73,7 +73,7 @@ func (s *SchemaBuilder) parseTypeDef(def *TypeDefinition) *spec.SchemaRef {
 	return schemaRef
 }
 
-func (s *SchemaBuilder) parseTypeSpec(t *ast.TypeSpec) *spec.SchemaRef {
+func (s *SchemaBuilder) parseTypeSpec(t *ast.TypeSpec, commentGroup *ast.CommentGroup) *spec.SchemaRef {
 	var typeParams []*spec.TypeParam
 	if t.TypeParams != nil {
 		for i, field := range t.TypeParams.List {
@@ -87,7 +87,18 @@ func (s *SchemaBuilder) parseTypeSpec(t *ast.TypeSpec) *spec.SchemaRef {
 		}
 	}
 
-	schema := s.setTypeParams(typeParams).ParseExpr(t.Type)
+	if commentGroup == nil {
+		commentGroup = s.ctx.GetHeadingCommentOf(t.Type.Pos())
+	}
+	comment := s.ctx.ParseComment(commentGroup)
+	comment.ReportUnknownAnnotations(s.ctx, "type "+s.ctx.Package().Name+"."+t.Name.Name)
+	var schema *spec.SchemaRef
+	if _, ok := t.Type.(*ast.InterfaceType); ok {
+		schema = s.parseInterfaceTypeSpec(t, comment)
+	}
+	if schema == nil {
+		schema = s.setTypeParams(typeParams).ParseExpr(t.Type)
+	}
 	if schema == nil {
 		return nil
 	}
@@ -95,14 +106,112 @@ func (s *SchemaBuilder) parseTypeSpec(t *ast.TypeSpec) *spec.SchemaRef {
 		schema.ExtendedTypeInfo.TypeParams = typeParams
 	}
 
-	comment := s.ctx.ParseComment(s.ctx.GetHeadingCommentOf(t.Type.Pos()))
 	comment.ApplyToSchema(schema)
 	if schema.Ref == "" {
+		// title 会被代码生成器用作类型名, 因此不从注释中获取. 注释只作为 description
 		schema.Title = strcase.ToCamel(s.ctx.Package().Name + t.Name.Name)
 	}
 	return schema
 }
 
+// parseInterfaceTypeSpec 将接口类型解析为 oneOf. 候选类型来自 @oneOf 注解,
+// 或者是 sealed interface (包含未导出的标记方法) 在当前模块内的所有实现
+func (s *SchemaBuilder) parseInterfaceTypeSpec(t *ast.TypeSpec, comment *Comment) *spec.SchemaRef {
+	if len(comment.OneOf()) > 0 {
+		return s.parseOneOf(comment, t.Pos())
+	}
+
+	named, ok := s.ctx.Package().TypesInfo.Defs[t.Name].Type().(*types.Named)
+	if !ok || !isSealedInterface(named) {
+		return nil
+	}
+	// 只有声明了 @discriminator 时才需要 mapping
+	discriminator := comment.Discriminator()
+	var variants []*spec.SchemaRef
+	var mapping = make(map[string]string)
+	for _, def := range s.ctx.ImplementationsOf(named) {
+		obj := def.Pkg().Types.Scope().Lookup(def.Spec.Name.Name)
+		variant := s.parseType(obj.Type())
+		variants = append(variants, variant)
+		if discriminator == "" {
+			continue
+		}
+		ctx := s.ctx.WithPackage(def.Pkg()).WithFile(def.File())
+		value := ctx.ParseComment(def.CommentGroup()).DiscriminatorValue()
+		if value == "" {
+			value = def.Spec.Name.Name
+		}
+		mapping[value] = variant.Ref
+	}
+	if len(variants) == 0 {
+		s.ctx.StrictWarn("no implementation found for sealed interface %s at %s", named.String(), s.ctx.LineColumn(t.Pos()))
+		return nil
+	}
+
+	return s.newOneOfSchema(variants, discriminator, mapping)
+}
+
+// parseOneOf 解析 @oneOf 注解声明的候选类型
+func (s *SchemaBuilder) parseOneOf(comment *Comment, pos token.Pos) *spec.SchemaRef {
+	var variants []*spec.SchemaRef
+	var mapping = make(map[string]string)
+	for _, item := range comment.OneOf() {
+		value, typeExpr, ok := strings.Cut(item, "=")
+		if !ok {
+			typeExpr = item
+			value = ""
+		}
+		t, err := s.ctx.ParseTypeExpr(typeExpr)
+		if err != nil {
+			s.ctx.StrictError("[Invalid Annotation]: @oneOf %s at %s", err.Error(), s.ctx.LineColumn(pos))
+			continue
+		}
+		variant := s.parseType(t)
+		if variant == nil {
+			continue
+		}
+		variants = append(variants, variant)
+		if value == "" {
+			if named, ok := t.(*types.Named); ok {
+				value = named.Obj().Name()
+			}
+		}
+		if value != "" && variant.Ref != "" {
+			mapping[value] = variant.Ref
+		}
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+
+	return s.newOneOfSchema(variants, comment.Discriminator(), mapping)
+}
+
+func (s *SchemaBuilder) newOneOfSchema(variants []*spec.SchemaRef, discriminator string, mapping map[string]string) *spec.SchemaRef {
+	schema := spec.NewOneOfSchema(variants...)
+	if discriminator != "" {
+		schema.Discriminator = &spec.Discriminator{PropertyName: discriminator}
+		if len(mapping) > 0 {
+			schema.Discriminator.Mapping = mapping
+		}
+	}
+	return schema
+}
+
+// isSealedInterface 判断是否为 sealed interface. 即包含未导出方法的接口, 只能被同一个包内的类型实现
+func isSealedInterface(t *types.Named) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		if !iface.Method(i).Exported() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SchemaBuilder) setTypeParams(params []*spec.TypeParam) *SchemaBuilder {
 	s.typeParams = params
 	return s
@@ -212,6 +321,12 @@ func (s *SchemaBuilder) parseStruct(expr *ast.StructType) *spec.SchemaRef {
 					for name, value := range fieldSchema.Properties {
 						schema.Properties[name] = value
 					}
+					// required fields of embedded struct, in order of declaration
+					for _, name := range fieldSchema.Required {
+						if !lo.Contains(schema.Required, name) {
+							schema.Required = append(schema.Required, name)
+						}
+					}
 				}
 			}
 		}
@@ -220,7 +335,12 @@ func (s *SchemaBuilder) parseStruct(expr *ast.StructType) *spec.SchemaRef {
 			if !name.IsExported() {
 				continue
 			}
-			fieldSchema := s.ParseExpr(field.Type)
+			var fieldSchema *spec.SchemaRef
+			if len(comment.OneOf()) > 0 {
+				fieldSchema = s.parseOneOf(comment, field.Pos())
+			} else {
+				fieldSchema = s.ParseExpr(field.Type)
+			}
 			if fieldSchema == nil {
 				s.ctx.StrictWarn("unknown field type %s at %s", name.Name, s.ctx.LineColumn(field.Type.Pos()))
 				continue
@@ -248,7 +368,26 @@ func (s *SchemaBuilder) parseIdent(expr *ast.Ident) *spec.SchemaRef {
 	if t == nil {
 		return nil
 	}
-	return s.parseType(t)
+	schema := s.parseType(t)
+	s.applyAliasComment(expr, schema)
+	return schema
+}
+
+// applyAliasComment 类型别名会被 go/types 直接解析为原类型, 因此需要单独将别名的注释作为内联 schema 的描述
+func (s *SchemaBuilder) applyAliasComment(expr *ast.Ident, schema *spec.SchemaRef) {
+	if schema == nil || schema.Ref != "" || schema.Description != "" {
+		return
+	}
+	obj, ok := s.ctx.Package().TypesInfo.Uses[expr].(*types.TypeName)
+	if !ok || !obj.IsAlias() || obj.Pkg() == nil {
+		return
+	}
+	def, ok := s.ctx.GetDefinition(obj.Pkg().Path(), obj.Name()).(*TypeDefinition)
+	if !ok {
+		return
+	}
+	ctx := s.ctx.WithPackage(def.Pkg()).WithFile(def.File())
+	schema.Description = ctx.ParseComment(def.CommentGroup()).Text()
 }
 
 var commonTypes = map[string]*spec.Schema{
@@ -272,6 +411,25 @@ var commonTypes = map[string]*spec.Schema{
 	"database/sql.NullByte":    spec.NewStringSchema(),
 }
 
+// resolvable reports whether schema of t can be built. Named types must be well-known types or have definitions.
+func (s *SchemaBuilder) resolvable(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return true
+	case *types.Pointer:
+		return s.resolvable(t.Elem())
+	case *types.Slice:
+		return s.resolvable(t.Elem())
+	case *types.Array:
+		return s.resolvable(t.Elem())
+	case *types.Map:
+		return s.resolvable(t.Key()) && s.resolvable(t.Elem())
+	case *types.Named:
+		return s.commonUsedType(t) != nil || s.ctx.ParseType(t) != nil
+	}
+	return false
+}
+
 func (s *SchemaBuilder) commonUsedType(t types.Type) *spec.SchemaRef {
 	switch t := t.(type) {
 	case *types.Named:
@@ -430,14 +588,33 @@ func (s *SchemaBuilder) parseType(t types.Type) *spec.SchemaRef {
 }
 
 func (s *SchemaBuilder) parseCommentOfField(field *ast.Field) *Comment {
-	// heading comment
-	if field.Doc != nil && len(field.Doc.List) > 0 {
-		return ParseCommentWithContext(field.Doc, s.ctx.Package().Fset, s.ctx)
+	comment := ParseCommentWithContext(commentGroupOfField(s.ctx, field), s.ctx.Package().Fset, s.ctx)
+	comment.ReportUnknownAnnotations(s.ctx, fieldOwnerOf(field))
+	return comment
+}
+
+// fieldOwnerOf describes the field in diagnostics
+func fieldOwnerOf(field *ast.Field) string {
+	var names []string
+	for _, name := range field.Names {
+		names = append(names, name.Name)
 	}
+	if len(names) == 0 {
+		return "embedded field"
+	}
+	return "field " + strings.Join(names, ", ")
+}
 
-	// parse trailing comment
-	commentGroup := s.ctx.GetTrailingCommentOf(field.Pos())
-	return ParseCommentWithContext(commentGroup, s.ctx.Package().Fset, s.ctx)
+// commentGroupOfField returns heading comment followed by trailing comment of the field
+func commentGroupOfField(ctx *Context, field *ast.Field) *ast.CommentGroup {
+	trailing := field.Comment
+	if trailing == nil {
+		trailing = ctx.GetTrailingCommentOf(field.Pos())
+		if trailing == field.Doc {
+			trailing = nil
+		}
+	}
+	return joinCommentGroups(field.Doc, trailing)
 }
 
 func (s *SchemaBuilder) parseCallExpr(expr *ast.CallExpr) *spec.SchemaRef {
@@ -0,0 +1,290 @@
+package eapi
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/chenwei67/eapi/utils"
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	SchemaNamingShort            = "short"             // User
+	SchemaNamingPackageQualified = "package-qualified" // model.User
+	SchemaNamingFullPath         = "full-path"         // github.com_org_repo_model.User (default)
+)
+
+// naming styles of instantiated generic types
+const (
+	GenericNamingBrackets   = "brackets"   // Resp[User] (default)
+	GenericNamingConcat     = "concat"     // RespUser
+	GenericNamingUnderscore = "underscore" // Resp_User
+	GenericNamingGuillemets = "guillemets" // Resp«User»
+)
+
+type SchemaNamingConfig struct {
+	// short | package-qualified | full-path. Default to full-path
+	Strategy string
+	// Custom name template. e.g. "{{.Package}}_{{.Name}}". Available fields: Name, Package, PkgPath
+	Template string
+	// Naming style of instantiated generic types: brackets | concat | underscore | guillemets
+	Generic string
+	// Rename specific types
+	Overrides []*SchemaNameOverride
+}
+
+type SchemaNameOverride struct {
+	Type string // full type name. e.g. "github.com/org/repo/model.User"
+	Name string
+}
+
+type schemaNameData struct {
+	Name    string
+	Package string
+	PkgPath string
+}
+
+type schemaNamer struct {
+	config    *SchemaNamingConfig
+	template  *template.Template
+	overrides map[string]string
+	types     modelTypes
+}
+
+func newSchemaNamer(config *SchemaNamingConfig, definitions Definitions) (*schemaNamer, error) {
+	n := &schemaNamer{
+		config:    config,
+		overrides: make(map[string]string),
+		types:     newModelTypes(definitions),
+	}
+	switch config.Strategy {
+	case "", SchemaNamingShort, SchemaNamingPackageQualified, SchemaNamingFullPath:
+	default:
+		return nil, fmt.Errorf("invalid schemaNaming.strategy %q. available: %s, %s, %s", config.Strategy, SchemaNamingShort, SchemaNamingPackageQualified, SchemaNamingFullPath)
+	}
+	switch config.Generic {
+	case "", GenericNamingBrackets, GenericNamingConcat, GenericNamingUnderscore, GenericNamingGuillemets:
+	default:
+		return nil, fmt.Errorf("invalid schemaNaming.generic %q. available: %s, %s, %s, %s", config.Generic, GenericNamingBrackets, GenericNamingConcat, GenericNamingUnderscore, GenericNamingGuillemets)
+	}
+	if config.Template != "" {
+		tpl, err := template.New("schemaNaming").Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schemaNaming.template: %w", err)
+		}
+		n.template = tpl
+	}
+	for _, item := range config.Overrides {
+		if item == nil || item.Type == "" || item.Name == "" {
+			return nil, fmt.Errorf("invalid schemaNaming.overrides: both 'type' and 'name' are required")
+		}
+		n.overrides[item.Type] = item.Name
+	}
+	return n, nil
+}
+
+// apply renames component schemas of doc. Conflicting names are suffixed with sequence number.
+func (n *schemaNamer) apply(doc *spec.T) error {
+	keys := utils.SortedKeys(doc.Components.Schemas)
+	var names = make(map[string]string, len(keys))
+	var owners = make(map[string][]string)
+	for _, key := range keys {
+		name, err := n.nameOf(key)
+		if err != nil {
+			return err
+		}
+		names[key] = name
+		owners[name] = append(owners[name], key)
+	}
+
+	var used = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		used[name] = struct{}{}
+	}
+	for _, name := range utils.SortedKeys(owners) {
+		conflicts := owners[name]
+		if len(conflicts) <= 1 {
+			continue
+		}
+		var types, renamed []string
+		for i, key := range conflicts {
+			types = append(types, n.types.goTypeOf(key))
+			if i > 0 {
+				newName := name
+				for seq := i + 1; ; seq++ {
+					newName = name + strconv.Itoa(seq)
+					if _, ok := used[newName]; !ok {
+						break
+					}
+				}
+				used[newName] = struct{}{}
+				names[key] = newName
+			}
+			renamed = append(renamed, names[key])
+		}
+		LogWarn("schema name %q is used by multiple types: %s. renamed to: %s", name, strings.Join(types, ", "), strings.Join(renamed, ", "))
+	}
+
+	for key, name := range names {
+		if key == name {
+			delete(names, key)
+			continue
+		}
+		schema := doc.Components.Schemas[key]
+		if schema == nil || schema.Title == "" {
+			continue
+		}
+		if schema.SpecializedFromGeneric && n.config.Generic != "" && n.config.Generic != GenericNamingBrackets {
+			schema.Title = name
+		} else if n.renamesTitle(key) {
+			schema.Title = strcase.ToCamel(name)
+		}
+	}
+	doc.RenameSchemas(names)
+	return nil
+}
+
+// nameOf returns name of component schema. key is in format of "pkg_path.Name[Arg1,Arg2]"
+func (n *schemaNamer) nameOf(key string) (string, error) {
+	base, args := splitModelKey(key)
+	name, err := n.baseNameOf(base)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return name, nil
+	}
+	var argNames []string
+	for _, arg := range args {
+		argName, err := n.nameOf(arg)
+		if err != nil {
+			return "", err
+		}
+		argNames = append(argNames, argName)
+	}
+	switch n.config.Generic {
+	case GenericNamingConcat:
+		for i, argName := range argNames {
+			argNames[i] = upperFirst(argName)
+		}
+		return name + strings.Join(argNames, ""), nil
+	case GenericNamingUnderscore:
+		return name + "_" + strings.Join(argNames, "_"), nil
+	case GenericNamingGuillemets:
+		return name + "«" + strings.Join(argNames, ",") + "»", nil
+	default:
+		return name + "[" + strings.Join(argNames, ",") + "]", nil
+	}
+}
+
+func (n *schemaNamer) baseNameOf(key string) (string, error) {
+	def, ok := n.types[key]
+	if !ok {
+		return key, nil
+	}
+	if name, ok := n.overrides[def.Key()]; ok {
+		return name, nil
+	}
+	data := schemaNameData{Name: def.Spec.Name.Name, Package: def.pkg.Name, PkgPath: def.pkg.PkgPath}
+	if n.template != nil {
+		var buf bytes.Buffer
+		err := n.template.Execute(&buf, data)
+		if err != nil {
+			return "", fmt.Errorf("execute schemaNaming.template failed on type %s: %w", def.Key(), err)
+		}
+		return buf.String(), nil
+	}
+	switch n.config.Strategy {
+	case SchemaNamingShort:
+		return data.Name, nil
+	case SchemaNamingPackageQualified:
+		return data.Package + "." + data.Name, nil
+	default:
+		return key, nil
+	}
+}
+
+// renamesTitle reports whether title of the schema should follow its new name
+func (n *schemaNamer) renamesTitle(key string) bool {
+	base, args := splitModelKey(key)
+	if len(args) > 0 {
+		return false
+	}
+	def, ok := n.types[base]
+	if !ok {
+		return false
+	}
+	if _, ok := n.overrides[def.Key()]; ok {
+		return true
+	}
+	return n.template != nil || n.config.Strategy == SchemaNamingShort || n.config.Strategy == SchemaNamingPackageQualified
+}
+
+// modelTypes is the type definitions of component schemas. key: model key
+type modelTypes map[string]*TypeDefinition
+
+func newModelTypes(definitions Definitions) modelTypes {
+	res := make(modelTypes)
+	for _, def := range definitions {
+		typeDef, ok := def.(*TypeDefinition)
+		if ok {
+			res[typeDef.ModelKey()] = typeDef
+		}
+	}
+	return res
+}
+
+// goTypeOf returns full name of Go type of the component schema. e.g. "github.com/org/repo/model.Resp[...]".
+// key is returned if the schema is not declared by Go type
+func (t modelTypes) goTypeOf(key string) string {
+	base, args := splitModelKey(key)
+	def, ok := t[base]
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return def.Key()
+	}
+	var argTypes []string
+	for _, arg := range args {
+		argTypes = append(argTypes, t.goTypeOf(arg))
+	}
+	return def.Key() + "[" + strings.Join(argTypes, ",") + "]"
+}
+
+// splitModelKey splits "pkg.Name[A,B[C]]" into "pkg.Name" and ["A", "B[C]"]
+func splitModelKey(key string) (string, []string) {
+	start := strings.Index(key, "[")
+	if start < 0 || !strings.HasSuffix(key, "]") {
+		return key, nil
+	}
+
+	var args []string
+	depth, last := 0, start+1
+	for i := start + 1; i < len(key)-1; i++ {
+		switch key[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, key[last:i])
+				last = i + 1
+			}
+		}
+	}
+	args = append(args, key[last:len(key)-1])
+	return key[:start], args
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
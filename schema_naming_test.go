@@ -0,0 +1,162 @@
+package eapi
+
+import (
+	"go/ast"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/chenwei67/eapi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func newNamingDefinitions(types ...string) Definitions {
+	definitions := make(Definitions)
+	for _, typ := range types {
+		pkgPath, name := splitTypeName(typ)
+		pkg := &packages.Package{PkgPath: pkgPath, Name: path.Base(pkgPath)}
+		definitions.Set(NewTypeDefinition(pkg, nil, &ast.TypeSpec{Name: ast.NewIdent(name)}))
+	}
+	return definitions
+}
+
+// splitTypeName splits "github.com/org/repo/model.User" into package path and type name
+func splitTypeName(typ string) (string, string) {
+	i := strings.LastIndex(typ, ".")
+	return typ[:i], typ[i+1:]
+}
+
+// newNamingDoc returns a document with a component schema for each key, and an operation referencing all of them
+func newNamingDoc(keys ...string) *spec.T {
+	doc := &spec.T{Info: &spec.Info{}, Paths: make(spec.Paths), Components: spec.Components{Schemas: make(spec.Schemas)}}
+	properties := make(spec.Schemas)
+	for _, key := range keys {
+		schema := spec.NewObjectSchema()
+		schema.Title = "Title"
+		doc.Components.Schemas[key] = schema
+		properties[key] = spec.RefSchema("#/components/schemas/" + key)
+	}
+	body := spec.NewObjectSchema().WithProperties(properties)
+	doc.AddOperation("/", "POST", &spec.Operation{
+		RequestBody: spec.NewRequestBody().WithJSONSchema(body),
+	})
+	return doc
+}
+
+func applySchemaNaming(t *testing.T, config *SchemaNamingConfig, definitions Definitions, doc *spec.T) []string {
+	namer, err := newSchemaNamer(config, definitions)
+	require.NoError(t, err)
+	require.NoError(t, namer.apply(doc))
+	return utils.SortedKeys(doc.Components.Schemas)
+}
+
+// refsOf returns the refs of properties of the request body
+func refsOf(doc *spec.T) []string {
+	var refs []string
+	body := doc.Paths["/"].Post.RequestBody.Content["application/json"].Schema
+	for _, key := range utils.SortedKeys(body.Properties) {
+		refs = append(refs, body.Properties[key].Ref)
+	}
+	return refs
+}
+
+func TestSchemaNamer_Strategy(t *testing.T) {
+	definitions := newNamingDefinitions("github.com/org/repo/model.User")
+	tests := []struct {
+		strategy string
+		want     string
+		title    string
+	}{
+		{strategy: "", want: "github.com_org_repo_model.User", title: "Title"},
+		{strategy: SchemaNamingFullPath, want: "github.com_org_repo_model.User", title: "Title"},
+		{strategy: SchemaNamingShort, want: "User", title: "User"},
+		{strategy: SchemaNamingPackageQualified, want: "model.User", title: "ModelUser"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			doc := newNamingDoc("github.com_org_repo_model.User")
+			keys := applySchemaNaming(t, &SchemaNamingConfig{Strategy: tt.strategy}, definitions, doc)
+			assert.Equal(t, []string{tt.want}, keys)
+			assert.Equal(t, tt.title, doc.Components.Schemas[tt.want].Title)
+			assert.Equal(t, []string{"#/components/schemas/" + tt.want}, refsOf(doc))
+		})
+	}
+}
+
+func TestSchemaNamer_Conflicts(t *testing.T) {
+	definitions := newNamingDefinitions("a/model.User", "b/model.User", "c/model.User2")
+	doc := newNamingDoc("a_model.User", "b_model.User", "c_model.User2")
+
+	var keys []string
+	warnings := captureWarnings(t, func() {
+		keys = applySchemaNaming(t, &SchemaNamingConfig{Strategy: SchemaNamingShort}, definitions, doc)
+	})
+	// "User2" is used by c/model.User2, so the second "User" is renamed to "User3"
+	assert.Equal(t, []string{"User", "User2", "User3"}, keys)
+	assert.Equal(t, []string{
+		"#/components/schemas/User",
+		"#/components/schemas/User3",
+		"#/components/schemas/User2",
+	}, refsOf(doc))
+	assert.Equal(t, "[WARN] schema name \"User\" is used by multiple types: a/model.User, b/model.User. renamed to: User, User3\n", warnings)
+}
+
+func TestSchemaNamer_Template(t *testing.T) {
+	definitions := newNamingDefinitions("github.com/org/repo/model.User")
+	doc := newNamingDoc("github.com_org_repo_model.User", "Custom")
+	keys := applySchemaNaming(t, &SchemaNamingConfig{
+		Strategy: SchemaNamingShort,
+		Template: "{{.Package}}_{{.Name}}",
+	}, definitions, doc)
+	// template takes precedence over strategy, and schemas not declared by Go types are kept
+	assert.Equal(t, []string{"Custom", "model_User"}, keys)
+
+	_, err := newSchemaNamer(&SchemaNamingConfig{Template: "{{.Name"}, definitions)
+	assert.ErrorContains(t, err, "invalid schemaNaming.template")
+}
+
+func TestSchemaNamer_Overrides(t *testing.T) {
+	definitions := newNamingDefinitions("a/model.User", "a/model.Group")
+	doc := newNamingDoc("a_model.User", "a_model.Group")
+	keys := applySchemaNaming(t, &SchemaNamingConfig{
+		Strategy:  SchemaNamingPackageQualified,
+		Overrides: []*SchemaNameOverride{{Type: "a/model.User", Name: "Account"}},
+	}, definitions, doc)
+	assert.Equal(t, []string{"Account", "model.Group"}, keys)
+	assert.Equal(t, "Account", doc.Components.Schemas["Account"].Title)
+
+	_, err := newSchemaNamer(&SchemaNamingConfig{Overrides: []*SchemaNameOverride{{Type: "a/model.User"}}}, definitions)
+	assert.ErrorContains(t, err, "both 'type' and 'name' are required")
+}
+
+func TestSchemaNamer_Generic(t *testing.T) {
+	definitions := newNamingDefinitions("a/model.Resp", "a/model.User")
+	tests := []struct {
+		generic string
+		want    string
+	}{
+		{generic: "", want: "Resp[User]"},
+		{generic: GenericNamingBrackets, want: "Resp[User]"},
+		{generic: GenericNamingConcat, want: "RespUser"},
+		{generic: GenericNamingUnderscore, want: "Resp_User"},
+		{generic: GenericNamingGuillemets, want: "Resp«User»"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.generic, func(t *testing.T) {
+			doc := newNamingDoc("a_model.Resp[a_model.User]")
+			keys := applySchemaNaming(t, &SchemaNamingConfig{Strategy: SchemaNamingShort, Generic: tt.generic}, definitions, doc)
+			assert.Equal(t, []string{tt.want}, keys)
+		})
+	}
+}
+
+func TestNewSchemaNamer_Invalid(t *testing.T) {
+	_, err := newSchemaNamer(&SchemaNamingConfig{Strategy: "long"}, nil)
+	assert.ErrorContains(t, err, `invalid schemaNaming.strategy "long"`)
+
+	_, err = newSchemaNamer(&SchemaNamingConfig{Generic: "angle"}, nil)
+	assert.ErrorContains(t, err, `invalid schemaNaming.generic "angle"`)
+}
@@ -0,0 +1,25 @@
+package eapi
+
+import (
+	"github.com/chenwei67/eapi/spec"
+	"github.com/chenwei67/eapi/utils"
+)
+
+// checkSecurity warns about security requirements which reference schemes not defined in components.securitySchemes
+func checkSecurity(doc *spec.T) {
+	check := func(requirements spec.SecurityRequirements, owner string) {
+		for _, requirement := range requirements {
+			for _, name := range utils.SortedKeys(requirement) {
+				if _, ok := doc.Components.SecuritySchemes[name]; !ok {
+					LogWarn("security scheme %q used by %s is not defined in openapi.securitySchemes", name, owner)
+				}
+			}
+		}
+	}
+	check(doc.Security, "openapi.security")
+	doc.Operations(func(path, method string, operation *spec.Operation) {
+		if operation.Security != nil {
+			check(*operation.Security, "operation "+method+" "+path)
+		}
+	})
+}
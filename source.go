@@ -0,0 +1,101 @@
+package eapi
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/chenwei67/eapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// AddSourceExtensions adds spec.ExtensionGoSource to the operations of doc, and spec.ExtensionGoSource and
+// spec.ExtensionGoType to the component schemas declared by Go types. Schemas must not be renamed yet, that is,
+// keys of component schemas are model keys.
+func (a *Analyzer) AddSourceExtensions(doc *spec.T) {
+	for _, route := range a.routes {
+		if route.Spec == nil || route.Spec.source == nil {
+			continue
+		}
+		pathItem := doc.Paths[route.FullPath]
+		if pathItem == nil {
+			continue
+		}
+		operation := pathItem.GetOperation(route.Method)
+		if operation != nil {
+			setExtension(&operation.ExtensionProps, spec.ExtensionGoSource, route.Spec.source)
+		}
+	}
+
+	types := newModelTypes(a.definitions)
+	for key, schema := range doc.Components.Schemas {
+		if schema == nil || schema.Ref != "" {
+			continue
+		}
+		base, _ := splitModelKey(key)
+		def, ok := types[base]
+		if !ok {
+			continue
+		}
+		setExtension(&schema.ExtensionProps, spec.ExtensionGoType, types.goTypeOf(key))
+		if source := sourceLocationOf(def.pkg, def.Spec.Pos()); source != nil {
+			setExtension(&schema.ExtensionProps, spec.ExtensionGoSource, source)
+		}
+	}
+}
+
+func setExtension(props *spec.ExtensionProps, name string, value interface{}) {
+	if props.Extensions == nil {
+		props.Extensions = make(map[string]interface{})
+	}
+	props.Extensions[name] = value
+}
+
+// sourceLocationOf returns location of pos in pkg. The file is relative to the module root so that the output does
+// not depend on the machine, and files of dependencies are prefixed with "module@version/"
+func sourceLocationOf(pkg *packages.Package, pos token.Pos) *spec.SourceLocation {
+	if pkg == nil || pkg.Module == nil || pkg.Module.Dir == "" || !pos.IsValid() {
+		return nil
+	}
+	position := pkg.Fset.Position(pos)
+	file, err := filepath.Rel(pkg.Module.Dir, position.Filename)
+	if err != nil || strings.HasPrefix(file, "..") {
+		return nil
+	}
+	file = filepath.ToSlash(file)
+	if !pkg.Module.Main {
+		module := pkg.Module.Path
+		if pkg.Module.Version != "" {
+			module += "@" + pkg.Module.Version
+		}
+		file = module + "/" + file
+	}
+	return &spec.SourceLocation{File: file, Line: position.Line}
+}
+
+// funcNameOf returns name of the function as in stack traces. e.g. "handler.(*UserHandler).Create"
+func funcNameOf(pkg *packages.Package, decl *ast.FuncDecl) string {
+	name := decl.Name.Name
+	if decl.Recv.NumFields() == 1 {
+		recv := decl.Recv.List[0].Type
+		star, ok := recv.(*ast.StarExpr)
+		if ok {
+			recv = star.X
+		}
+		switch t := recv.(type) {
+		case *ast.IndexExpr:
+			recv = t.X
+		case *ast.IndexListExpr:
+			recv = t.X
+		}
+		if ident, isIdent := recv.(*ast.Ident); isIdent {
+			if ok {
+				name = "(*" + ident.Name + ")." + name
+			} else {
+				name = ident.Name + "." + name
+			}
+		}
+	}
+	return pkg.Name + "." + name
+}
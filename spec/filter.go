@@ -0,0 +1,54 @@
+package spec
+
+import "github.com/chenwei67/eapi/utils"
+
+// FilterOperations returns a copy of doc which contains only the operations accepted by keep. Component schemas
+// which are no longer referenced and tags which are no longer used by any operation are removed as well.
+// Operations and schemas are shared with doc.
+func (doc *T) FilterOperations(keep func(path, method string, operation *Operation) bool) *T {
+	res := *doc
+	if doc.Info != nil {
+		info := *doc.Info
+		res.Info = &info
+	}
+
+	res.Paths = make(Paths)
+	usedTags := make(map[string]struct{})
+	for _, path := range utils.SortedKeys(doc.Paths) {
+		pathItem := doc.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		filtered := *pathItem
+		var kept bool
+		for method, operation := range pathItem.Operations() {
+			if !keep(path, method, operation) {
+				filtered.SetOperation(method, nil)
+				continue
+			}
+			kept = true
+			for _, tag := range operation.Tags {
+				usedTags[tag] = struct{}{}
+			}
+		}
+		if kept {
+			res.Paths[path] = &filtered
+		}
+	}
+
+	res.Tags = nil
+	for _, tag := range doc.Tags {
+		if _, ok := usedTags[tag.Name]; ok {
+			res.Tags = append(res.Tags, tag)
+		}
+	}
+
+	referenced := doc.ReferencedSchemas(res.usedSchemas()...)
+	res.Components.Schemas = make(Schemas, len(referenced))
+	for key := range referenced {
+		if schema, ok := doc.Components.Schemas[key]; ok {
+			res.Components.Schemas[key] = schema
+		}
+	}
+	return &res
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/chenwei67/eapi/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_FilterOperations(t *testing.T) {
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Tags:    Tags{{Name: "pet"}, {Name: "user"}},
+		Components: Components{
+			Schemas: Schemas{
+				"Pet":   NewObjectSchema().WithPropertyRef("owner", RefComponentSchemas("Owner")),
+				"Owner": NewObjectSchema().WithProperty("name", NewStringSchema()),
+				"Cat":   NewObjectSchema(),
+				"Animal": &Schema{
+					OneOf:         SchemaRefs{RefComponentSchemas("Pet")},
+					Discriminator: &Discriminator{PropertyName: "kind", Mapping: map[string]string{"cat": "#/components/schemas/Cat"}},
+				},
+				"User": NewObjectSchema(),
+			},
+		},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Get: &Operation{
+					Tags:      []string{"pet"},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("Animal"))},
+				},
+				Post: &Operation{
+					Tags:      []string{"user"},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("User"))},
+				},
+			},
+			"/users": &PathItem{
+				Get: &Operation{
+					Tags:      []string{"user"},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("User"))},
+				},
+			},
+		},
+	}
+
+	res := doc.FilterOperations(func(path, method string, operation *Operation) bool {
+		return len(operation.Tags) > 0 && operation.Tags[0] == "pet"
+	})
+	require.Len(t, res.Paths, 1)
+	require.NotNil(t, res.Paths["/pets"].Get)
+	require.Nil(t, res.Paths["/pets"].Post)
+	require.Equal(t, Tags{{Name: "pet"}}, res.Tags)
+	require.ElementsMatch(t, []string{"Animal", "Cat", "Owner", "Pet"}, utils.SortedKeys(res.Components.Schemas))
+
+	// doc is not modified
+	require.Len(t, doc.Paths, 2)
+	require.NotNil(t, doc.Paths["/pets"].Post)
+	require.Len(t, doc.Components.Schemas, 5)
+	res.Info.Title = "Pets"
+	require.Equal(t, "Example", doc.Info.Title)
+}
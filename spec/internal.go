@@ -0,0 +1,205 @@
+package spec
+
+// ExtensionInternal marks operations, parameters and schema properties which are hidden from public documents
+const ExtensionInternal = "x-internal"
+
+// IsInternal reports whether the object with props is marked with ExtensionInternal
+func IsInternal(props ExtensionProps) bool {
+	internal, _ := props.Extensions[ExtensionInternal].(bool)
+	return internal
+}
+
+// RemoveInternal returns copy of doc without the operations, parameters and schema properties marked with
+// ExtensionInternal. Component schemas which are only referenced by them are removed as well. doc is not modified.
+func (doc *T) RemoveInternal() *T {
+	res := doc.FilterOperations(func(path, method string, operation *Operation) bool {
+		return !IsInternal(operation.ExtensionProps)
+	})
+
+	s := make(internalStripper)
+	for _, pathItem := range res.Paths {
+		for method, operation := range pathItem.Operations() {
+			pathItem.SetOperation(method, s.operation(operation))
+		}
+	}
+	if doc.Webhooks != nil {
+		res.Webhooks = make(map[string]*PathItem, len(doc.Webhooks))
+		for name, pathItem := range doc.Webhooks {
+			res.Webhooks[name] = s.pathItem(pathItem)
+		}
+	}
+	schemas := make(Schemas, len(res.Components.Schemas))
+	for key, schema := range res.Components.Schemas {
+		schemas[key] = s.schema(schema)
+	}
+	res.Components.Schemas = schemas
+
+	// remove schemas referenced only by internal properties
+	return res.FilterOperations(func(path, method string, operation *Operation) bool { return true })
+}
+
+// internalStripper copies the objects which contain internal parts. key: original schema, value: stripped schema
+type internalStripper map[*Schema]*Schema
+
+func (s internalStripper) pathItem(pathItem *PathItem) *PathItem {
+	if pathItem == nil {
+		return nil
+	}
+	res := *pathItem
+	for method, operation := range pathItem.Operations() {
+		if IsInternal(operation.ExtensionProps) {
+			res.SetOperation(method, nil)
+		} else {
+			res.SetOperation(method, s.operation(operation))
+		}
+	}
+	res.Parameters = s.parameters(pathItem.Parameters)
+	return &res
+}
+
+func (s internalStripper) operation(operation *Operation) *Operation {
+	res := *operation
+	res.Parameters = s.parameters(operation.Parameters)
+	if operation.RequestBody != nil {
+		requestBody := *operation.RequestBody
+		requestBody.Content = s.content(requestBody.Content)
+		res.RequestBody = &requestBody
+	}
+	if operation.Responses != nil {
+		res.Responses = make(Responses, len(operation.Responses))
+		for code, response := range operation.Responses {
+			if response == nil {
+				res.Responses[code] = nil
+				continue
+			}
+			copied := *response
+			copied.Content = s.content(response.Content)
+			if response.Headers != nil {
+				copied.Headers = make(Headers, len(response.Headers))
+				for name, header := range response.Headers {
+					if header != nil && header.Value != nil {
+						value := *header.Value
+						value.Schema = s.schema(value.Schema)
+						value.Content = s.content(value.Content)
+						header = &HeaderRef{Ref: header.Ref, Value: &value}
+					}
+					copied.Headers[name] = header
+				}
+			}
+			res.Responses[code] = &copied
+		}
+	}
+	if operation.Callbacks != nil {
+		res.Callbacks = make(Callbacks, len(operation.Callbacks))
+		for name, callback := range operation.Callbacks {
+			if callback == nil || callback.Value == nil {
+				res.Callbacks[name] = callback
+				continue
+			}
+			value := make(Callback, len(*callback.Value))
+			for expression, pathItem := range *callback.Value {
+				value[expression] = s.pathItem(pathItem)
+			}
+			res.Callbacks[name] = &CallbackRef{Ref: callback.Ref, Value: &value}
+		}
+	}
+	return &res
+}
+
+func (s internalStripper) parameters(parameters Parameters) Parameters {
+	if parameters == nil {
+		return nil
+	}
+	res := make(Parameters, 0, len(parameters))
+	for _, parameter := range parameters {
+		if parameter == nil {
+			res = append(res, nil)
+			continue
+		}
+		if IsInternal(parameter.ExtensionProps) || parameter.Schema != nil && IsInternal(parameter.Schema.ExtensionProps) {
+			continue
+		}
+		copied := *parameter
+		copied.Schema = s.schema(parameter.Schema)
+		copied.Content = s.content(parameter.Content)
+		res = append(res, &copied)
+	}
+	return res
+}
+
+func (s internalStripper) content(content Content) Content {
+	if content == nil {
+		return nil
+	}
+	res := make(Content, len(content))
+	for mime, mediaType := range content {
+		if mediaType != nil {
+			copied := *mediaType
+			copied.Schema = s.schema(mediaType.Schema)
+			mediaType = &copied
+		}
+		res[mime] = mediaType
+	}
+	return res
+}
+
+// schema returns schema without internal properties. schema is copied only if it contains internal properties
+func (s internalStripper) schema(schema *Schema) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if res, ok := s[schema]; ok {
+		return res
+	}
+	s[schema] = schema // recursive schemas
+
+	res := *schema
+	changed := false
+	child := func(item *Schema) *Schema {
+		stripped := s.schema(item)
+		changed = changed || stripped != item
+		return stripped
+	}
+	children := func(items SchemaRefs) SchemaRefs {
+		if items == nil {
+			return nil
+		}
+		res := make(SchemaRefs, len(items))
+		for i, item := range items {
+			res[i] = child(item)
+		}
+		return res
+	}
+	res.Not = child(schema.Not)
+	res.Items = child(schema.Items)
+	res.AdditionalProperties = child(schema.AdditionalProperties)
+	res.OneOf = children(schema.OneOf)
+	res.AnyOf = children(schema.AnyOf)
+	res.AllOf = children(schema.AllOf)
+	if schema.Properties != nil {
+		res.Properties = make(Schemas, len(schema.Properties))
+		var removed = make(map[string]struct{})
+		for name, property := range schema.Properties {
+			if property != nil && IsInternal(property.ExtensionProps) {
+				removed[name] = struct{}{}
+				changed = true
+				continue
+			}
+			res.Properties[name] = child(property)
+		}
+		if len(removed) > 0 && schema.Required != nil {
+			res.Required = make([]string, 0, len(schema.Required))
+			for _, name := range schema.Required {
+				if _, ok := removed[name]; !ok {
+					res.Required = append(res.Required, name)
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return schema
+	}
+	s[schema] = &res
+	return &res
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/chenwei67/eapi/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_RemoveInternal(t *testing.T) {
+	internal := ExtensionProps{Extensions: map[string]interface{}{ExtensionInternal: true}}
+	secret := RefComponentSchemas("Secret")
+	secret.ExtensionProps = internal
+	token := NewStringSchema()
+	token.ExtensionProps = internal
+	user := NewObjectSchema().
+		WithProperty("name", NewStringSchema()).
+		WithPropertyRef("secret", secret).
+		WithProperty("token", token)
+	user.Required = []string{"name", "token"}
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Components: Components{
+			Schemas: Schemas{
+				"User":   user,
+				"Secret": NewObjectSchema(),
+				"Audit":  NewObjectSchema(),
+			},
+		},
+		Paths: Paths{
+			"/users": &PathItem{
+				Get: &Operation{
+					Parameters: Parameters{
+						NewQueryParameter("page").WithSchema(NewIntegerSchema()),
+						&Parameter{Name: "debug", In: ParameterInQuery, Schema: NewBoolSchema(), ExtensionProps: internal},
+					},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchema(NewArraySchema(RefComponentSchemas("User")))},
+				},
+				Delete: &Operation{
+					ExtensionProps: internal,
+					Responses:      Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("Audit"))},
+				},
+			},
+		},
+	}
+
+	res := doc.RemoveInternal()
+	require.Nil(t, res.Paths["/users"].Delete)
+	require.Len(t, res.Paths["/users"].Get.Parameters, 1)
+	require.Equal(t, "page", res.Paths["/users"].Get.Parameters[0].Name)
+	require.ElementsMatch(t, []string{"User"}, utils.SortedKeys(res.Components.Schemas))
+	require.ElementsMatch(t, []string{"name"}, utils.SortedKeys(res.Components.Schemas["User"].Properties))
+	require.Equal(t, []string{"name"}, res.Components.Schemas["User"].Required)
+
+	// doc is not modified
+	require.NotNil(t, doc.Paths["/users"].Delete)
+	require.Len(t, doc.Paths["/users"].Get.Parameters, 2)
+	require.Len(t, doc.Components.Schemas, 3)
+	require.Len(t, user.Properties, 3)
+	require.Equal(t, []string{"name", "token"}, user.Required)
+}
@@ -0,0 +1,509 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chenwei67/eapi/utils"
+)
+
+// jsonPath is a compiled JSONPath expression selecting nodes of JSON value which is decoded into
+// map[string]interface{}, []interface{} and primitive values. Only the subset of RFC 9535 used by targets of
+// overlays is supported:
+//
+//	$                        root
+//	.name ['name'] ["name"]  child by name
+//	[0] [-1]                 array element by index
+//	.* [*]                   all children
+//	..name ..* ..[...]       descendants (including the node itself)
+//	[?<filter>] [?(<filter>)] children matched by filter. e.g. [?@.in == 'header' && @.required]
+//
+// A filter is one or more conditions joined by &&. A condition is either an existence test of relative path (@.a.b)
+// or a comparison (== or !=) between relative path and literal (string, number, true, false or null).
+type jsonPath struct {
+	segments []*pathSegment
+}
+
+type pathSegment struct {
+	descendant bool
+	selector   pathSelector
+}
+
+type pathSelector struct {
+	name     *string
+	index    *int
+	wildcard bool
+	filter   []*pathCondition
+}
+
+// jsonLocation is the keys (string for objects and int for arrays) from root to the node
+type jsonLocation []interface{}
+
+func (l jsonLocation) child(key interface{}) jsonLocation {
+	res := make(jsonLocation, len(l), len(l)+1)
+	copy(res, l)
+	return append(res, key)
+}
+
+func compileJSONPath(expr string) (*jsonPath, error) {
+	p := &pathParser{input: expr}
+	res, err := p.parsePath()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	return res, nil
+}
+
+// selectLocations returns locations of the nodes selected by p in document order
+func (p *jsonPath) selectLocations(root interface{}) []jsonLocation {
+	nodes := []jsonLocation{{}}
+	for _, segment := range p.segments {
+		var next []jsonLocation
+		for _, node := range nodes {
+			value, _ := valueAt(root, node)
+			next = append(next, segment.apply(node, value)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (s *pathSegment) apply(location jsonLocation, value interface{}) []jsonLocation {
+	res := s.selector.apply(location, value)
+	if !s.descendant {
+		return res
+	}
+	for _, key := range childKeys(value) {
+		child, _ := childOf(value, key)
+		res = append(res, s.apply(location.child(key), child)...)
+	}
+	return res
+}
+
+func (s pathSelector) apply(location jsonLocation, value interface{}) []jsonLocation {
+	switch {
+	case s.name != nil:
+		if object, ok := value.(map[string]interface{}); ok {
+			if _, ok := object[*s.name]; ok {
+				return []jsonLocation{location.child(*s.name)}
+			}
+		}
+	case s.index != nil:
+		if array, ok := value.([]interface{}); ok {
+			index := *s.index
+			if index < 0 {
+				index += len(array)
+			}
+			if index >= 0 && index < len(array) {
+				return []jsonLocation{location.child(index)}
+			}
+		}
+	default:
+		var res []jsonLocation
+		for _, key := range childKeys(value) {
+			child, _ := childOf(value, key)
+			if s.matches(child) {
+				res = append(res, location.child(key))
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// matches reports whether value satisfies all the conditions of filter
+func (s pathSelector) matches(value interface{}) bool {
+	for _, condition := range s.filter {
+		if !condition.eval(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// childKeys returns keys of object (sorted) or indexes of array
+func childKeys(value interface{}) []interface{} {
+	var res []interface{}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, key := range utils.SortedKeys(value) {
+			res = append(res, key)
+		}
+	case []interface{}:
+		for i := range value {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func childOf(value interface{}, key interface{}) (interface{}, bool) {
+	switch key := key.(type) {
+	case string:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		res, ok := object[key]
+		return res, ok
+	case int:
+		array, ok := value.([]interface{})
+		if !ok || key < 0 || key >= len(array) {
+			return nil, false
+		}
+		return array[key], true
+	}
+	return nil, false
+}
+
+func valueAt(root interface{}, location jsonLocation) (interface{}, bool) {
+	value := root
+	for _, key := range location {
+		var ok bool
+		value, ok = childOf(value, key)
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setValueAt sets value at location and returns the new root
+func setValueAt(root interface{}, location jsonLocation, value interface{}) interface{} {
+	if len(location) == 0 {
+		return value
+	}
+	parent, _ := valueAt(root, location[:len(location)-1])
+	switch key := location[len(location)-1].(type) {
+	case string:
+		parent.(map[string]interface{})[key] = value
+	case int:
+		parent.([]interface{})[key] = value
+	}
+	return root
+}
+
+// removeValuesAt removes the nodes at locations and returns the new root. Locations are removed from the last one
+// so that indexes of the remaining elements are not changed
+func removeValuesAt(root interface{}, locations []jsonLocation) interface{} {
+	locations = append([]jsonLocation(nil), locations...)
+	sort.SliceStable(locations, func(i, j int) bool {
+		return compareLocations(locations[i], locations[j]) > 0
+	})
+	for i, location := range locations {
+		if len(location) == 0 || i > 0 && compareLocations(location, locations[i-1]) == 0 {
+			continue
+		}
+		parentLocation := location[:len(location)-1]
+		parent, ok := valueAt(root, parentLocation)
+		if !ok {
+			continue
+		}
+		switch key := location[len(location)-1].(type) {
+		case string:
+			delete(parent.(map[string]interface{}), key)
+		case int:
+			array := parent.([]interface{})
+			res := make([]interface{}, 0, len(array)-1)
+			res = append(res, array[:key]...)
+			res = append(res, array[key+1:]...)
+			root = setValueAt(root, parentLocation, res)
+		}
+	}
+	return root
+}
+
+func compareLocations(a, b jsonLocation) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch x := a[i].(type) {
+		case int:
+			y, ok := b[i].(int)
+			if !ok {
+				return 1
+			}
+			if x != y {
+				return x - y
+			}
+		case string:
+			y, ok := b[i].(string)
+			if !ok {
+				return -1
+			}
+			if x != y {
+				return strings.Compare(x, y)
+			}
+		}
+	}
+	return len(a) - len(b)
+}
+
+// String returns JSONPath of the location. e.g. $['paths']['/users']['get']
+func (l jsonLocation) String() string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, key := range l {
+		switch key := key.(type) {
+		case string:
+			sb.WriteString("['" + strings.ReplaceAll(key, "'", "\\'") + "']")
+		case int:
+			sb.WriteString("[" + strconv.Itoa(key) + "]")
+		}
+	}
+	return sb.String()
+}
+
+// pathCondition is condition of filter selector, which tests existence of the node selected by path relative to the
+// current node, or compares the node with literal if op is not empty
+type pathCondition struct {
+	path  *jsonPath
+	op    string
+	value interface{}
+}
+
+func (c *pathCondition) eval(current interface{}) bool {
+	locations := c.path.selectLocations(current)
+	if c.op == "" {
+		return len(locations) > 0
+	}
+	var equal bool
+	if len(locations) == 1 {
+		value, _ := valueAt(current, locations[0])
+		equal = reflect.DeepEqual(value, c.value)
+	}
+	return equal == (c.op == "==")
+}
+
+type pathParser struct {
+	input string
+	pos   int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *pathParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) parsePath() (*jsonPath, error) {
+	p.skipSpaces()
+	if !p.consume("$") {
+		return nil, p.errorf("must start with '$'")
+	}
+	res, err := p.parseSegments(false)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return res, nil
+}
+
+// parseSegments parses segments until the end of path. Relative paths in filter end at the first character which
+// can not start a segment
+func (p *pathParser) parseSegments(inFilter bool) (*jsonPath, error) {
+	res := &jsonPath{}
+	for p.pos < len(p.input) {
+		var segment *pathSegment
+		var err error
+		switch {
+		case strings.HasPrefix(p.input[p.pos:], ".."):
+			p.pos += 2
+			segment, err = p.parseSegment(true)
+		case p.input[p.pos] == '.':
+			p.pos++
+			segment, err = p.parseSegment(false)
+		case p.input[p.pos] == '[':
+			segment, err = p.parseSegment(false)
+		default:
+			if !inFilter && p.input[p.pos] != ' ' {
+				return nil, p.errorf("unexpected %q", p.input[p.pos:])
+			}
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.segments = append(res.segments, segment)
+	}
+	return res, nil
+}
+
+// parseSegment parses name, wildcard or bracketed selector after "." or ".."
+func (p *pathParser) parseSegment(descendant bool) (*pathSegment, error) {
+	res := &pathSegment{descendant: descendant}
+	if p.pos < len(p.input) && p.input[p.pos] == '[' {
+		selector, err := p.parseBracket()
+		if err != nil {
+			return nil, err
+		}
+		res.selector = selector
+		return res, nil
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '*' {
+		p.pos++
+		res.selector = pathSelector{wildcard: true}
+		return res, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("expect member name")
+	}
+	name := p.input[start:p.pos]
+	res.selector = pathSelector{name: &name}
+	return res, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func (p *pathParser) parseBracket() (pathSelector, error) {
+	p.pos++ // [
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return pathSelector{}, p.errorf("unclosed '['")
+	}
+	var res pathSelector
+	switch c := p.input[p.pos]; {
+	case c == '*':
+		p.pos++
+		res.wildcard = true
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return res, err
+		}
+		res.name = &name
+	case c == '?':
+		p.pos++
+		filter, err := p.parseFilter()
+		if err != nil {
+			return res, err
+		}
+		res.filter = filter
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil {
+			return res, p.errorf("invalid index %q", p.input[start:p.pos])
+		}
+		res.index = &index
+	default:
+		return res, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	if !p.consume("]") {
+		return res, p.errorf("expect ']'")
+	}
+	return res, nil
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.input[p.pos]
+	var sb strings.Builder
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.input):
+			p.pos++
+			switch escaped := p.input[p.pos]; escaped {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(escaped)
+			}
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unclosed string")
+}
+
+// parseFilter parses conditions joined by && after "?", which may be enclosed in parentheses
+func (p *pathParser) parseFilter() ([]*pathCondition, error) {
+	parenthesized := p.consume("(")
+	var res []*pathCondition
+	for {
+		condition, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, condition)
+		if !p.consume("&&") {
+			break
+		}
+	}
+	if parenthesized && !p.consume(")") {
+		return nil, p.errorf("expect ')'")
+	}
+	return res, nil
+}
+
+func (p *pathParser) parseCondition() (*pathCondition, error) {
+	if !p.consume("@") {
+		return nil, p.errorf("expect relative path starting with '@'")
+	}
+	path, err := p.parseSegments(true)
+	if err != nil {
+		return nil, err
+	}
+	res := &pathCondition{path: path}
+	for _, op := range []string{"==", "!="} {
+		if p.consume(op) {
+			res.op = op
+			res.value, err = p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return res, nil
+}
+
+func (p *pathParser) parseLiteral() (interface{}, error) {
+	p.skipSpaces()
+	if p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("+-.eE0123456789abcdefghijklmnopqrstuvwxyz", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	var value interface{}
+	err := json.Unmarshal([]byte(p.input[start:p.pos]), &value)
+	if err != nil || start == p.pos {
+		return nil, p.errorf("invalid literal %q", p.input[start:p.pos])
+	}
+	return value, nil
+}
@@ -0,0 +1,112 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	var root interface{}
+	err := json.Unmarshal([]byte(`{
+		"info": {"title": "Example", "x-logo": {"url": "logo.png"}},
+		"paths": {
+			"/users": {
+				"get": {"parameters": [{"in": "query", "name": "page"}, {"in": "header", "name": "X-Trace"}]},
+				"post": {"deprecated": true, "x-rate": 10}
+			},
+			"/users/{id}": {"get": {"parameters": [{"in": "path", "name": "id", "required": true}]}}
+		}
+	}`), &root)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "root", expr: "$", want: []string{"$"}},
+		{name: "dot name", expr: "$.info.title", want: []string{"$['info']['title']"}},
+		{name: "dot name with dash", expr: "$.info.x-logo.url", want: []string{"$['info']['x-logo']['url']"}},
+		{name: "single quoted name", expr: "$.paths['/users'].get", want: []string{"$['paths']['/users']['get']"}},
+		{name: "double quoted name", expr: `$["paths"]["/users/{id}"]`, want: []string{"$['paths']['/users/{id}']"}},
+		{name: "missing name", expr: "$.paths.nope", want: nil},
+		{name: "index", expr: "$.paths['/users'].get.parameters[1].name", want: []string{
+			"$['paths']['/users']['get']['parameters'][1]['name']",
+		}},
+		{name: "negative index", expr: "$.paths['/users'].get.parameters[-2]", want: []string{
+			"$['paths']['/users']['get']['parameters'][0]",
+		}},
+		{name: "index out of range", expr: "$.paths['/users'].get.parameters[2]", want: nil},
+		{name: "dot wildcard", expr: "$.paths.*.get", want: []string{
+			"$['paths']['/users']['get']",
+			"$['paths']['/users/{id}']['get']",
+		}},
+		{name: "bracket wildcard", expr: "$.paths['/users'][*]", want: []string{
+			"$['paths']['/users']['get']",
+			"$['paths']['/users']['post']",
+		}},
+		{name: "descendant name", expr: "$..required", want: []string{
+			"$['paths']['/users/{id}']['get']['parameters'][0]['required']",
+		}},
+		{name: "descendant wildcard", expr: "$.info..*", want: []string{
+			"$['info']['title']",
+			"$['info']['x-logo']",
+			"$['info']['x-logo']['url']",
+		}},
+		{name: "descendant bracket", expr: "$..parameters[0].in", want: []string{
+			"$['paths']['/users']['get']['parameters'][0]['in']",
+			"$['paths']['/users/{id}']['get']['parameters'][0]['in']",
+		}},
+		{name: "filter equal", expr: "$..parameters[?@.in == 'header']", want: []string{
+			"$['paths']['/users']['get']['parameters'][1]",
+		}},
+		{name: "filter not equal", expr: `$..parameters[?(@.in != "query")].name`, want: []string{
+			"$['paths']['/users']['get']['parameters'][1]['name']",
+			"$['paths']['/users/{id}']['get']['parameters'][0]['name']",
+		}},
+		{name: "filter existence", expr: "$.paths.*[?@.deprecated]", want: []string{"$['paths']['/users']['post']"}},
+		{name: "filter number and boolean", expr: "$.paths.*[?@.x-rate == 10 && @.deprecated == true]", want: []string{
+			"$['paths']['/users']['post']",
+		}},
+		{name: "filter nested path", expr: "$.paths[?(@.get.parameters[0].required == true)]", want: []string{
+			"$['paths']['/users/{id}']",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := compileJSONPath(tt.expr)
+			require.NoError(t, err)
+			var got []string
+			for _, location := range path.selectLocations(root) {
+				got = append(got, location.String())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONPath_Invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{expr: "paths", err: `invalid JSONPath "paths": at position 0: must start with '$'`},
+		{expr: "$.", err: `invalid JSONPath "$.": at position 2: expect member name`},
+		{expr: "$.paths[", err: `invalid JSONPath "$.paths[": at position 8: unclosed '['`},
+		{expr: "$.paths['a'", err: `invalid JSONPath "$.paths['a'": at position 11: expect ']'`},
+		{expr: "$.paths['a", err: `invalid JSONPath "$.paths['a": at position 10: unclosed string`},
+		{expr: "$.paths[0, 1]", err: `invalid JSONPath "$.paths[0, 1]": at position 9: expect ']'`},
+		{expr: "$.paths[?@.a == ]", err: `invalid JSONPath "$.paths[?@.a == ]": at position 16: invalid literal ""`},
+		{expr: "$.paths[?$.a]", err: `invalid JSONPath "$.paths[?$.a]": at position 9: expect relative path starting with '@'`},
+		{expr: "$.paths[?(@.a]", err: `invalid JSONPath "$.paths[?(@.a]": at position 13: expect ')'`},
+		{expr: "$.paths.'a'", err: `invalid JSONPath "$.paths.'a'": at position 8: expect member name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := compileJSONPath(tt.expr)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chenwei67/eapi/utils"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ToSwagger2 converts doc to Swagger 2.0 document. Constructs which can not be represented in Swagger 2.0
+// (e.g. oneOf, multiple content types of a response, cookie parameters) are dropped and returned as warnings.
+func (doc *T) ToSwagger2() (*openapi2.T, []string, error) {
+	warnings := doc.swagger2Warnings()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tree map[string]interface{}
+	err = json.Unmarshal(data, &tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(tree, "webhooks")
+	delete(tree, "jsonSchemaDialect")
+	walkJSONSchemas(tree, convertSchema2)
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc3 openapi3.T
+	err = json.Unmarshal(data, &doc3)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// host and basePath of Swagger 2.0 can not contain variables
+	for _, server := range doc3.Servers {
+		for name, variable := range server.Variables {
+			server.URL = strings.ReplaceAll(server.URL, "{"+name+"}", variable.Default)
+		}
+	}
+
+	// Swagger 2.0 supports only one schema for each response. Responses are declared in "produces" of operation
+	produces := make(map[*openapi3.Operation][]string)
+	for _, pathItem := range doc3.Paths {
+		for _, operation := range pathItem.Operations() {
+			produces[operation] = selectResponseContent(operation)
+		}
+	}
+
+	doc2, err := openapi2conv.FromV3(&doc3)
+	if err != nil {
+		return nil, nil, err
+	}
+	for path, pathItem := range doc3.Paths {
+		for method, operation := range pathItem.Operations() {
+			operation2 := doc2.Paths[path].GetOperation(method)
+			if operation2 == nil {
+				continue
+			}
+			operation2.Produces = produces[operation]
+			var parameters openapi2.Parameters
+			for _, parameter := range operation2.Parameters {
+				if parameter.In != ParameterInCookie {
+					parameters = append(parameters, parameter)
+				}
+			}
+			operation2.Parameters = parameters
+		}
+	}
+	return doc2, warnings, nil
+}
+
+// selectResponseContent keeps only one content of each response (application/json or the first one) as
+// openapi2conv only converts "application/json". Returns content types of the responses.
+func selectResponseContent(operation *openapi3.Operation) []string {
+	var produces = make(map[string]struct{})
+	for _, response := range operation.Responses {
+		if response == nil || response.Value == nil || len(response.Value.Content) == 0 {
+			continue
+		}
+		content := response.Value.Content
+		mimes := make([]string, 0, len(content))
+		for mime := range content {
+			mimes = append(mimes, mime)
+		}
+		sort.Strings(mimes)
+		for _, mime := range mimes {
+			produces[mime] = struct{}{}
+		}
+		selected := content["application/json"]
+		if selected == nil {
+			selected = content[mimes[0]]
+		}
+		response.Value.Content = openapi3.Content{"application/json": selected}
+	}
+	return utils.SortedKeys(produces)
+}
+
+// convertSchema2 removes the properties of schema which are not supported by Swagger 2.0
+func convertSchema2(schema map[string]interface{}) interface{} {
+	delete(schema, "ext")
+	delete(schema, "oneOf")
+	delete(schema, "anyOf")
+	// discriminator of Swagger 2.0 is only the property name, which can not be decoded by openapi3
+	delete(schema, "discriminator")
+	if nullable, ok := schema["nullable"]; ok {
+		delete(schema, "nullable")
+		schema["x-nullable"] = nullable
+	}
+	return schema
+}
+
+func (doc *T) swagger2Warnings() []string {
+	var warnings []string
+	for _, name := range utils.SortedKeys(doc.Components.Schemas) {
+		if hasOneOf(doc.Components.Schemas[name]) {
+			warnings = append(warnings, fmt.Sprintf("oneOf/anyOf in schema %s is not supported by Swagger 2.0", name))
+		}
+	}
+	if len(doc.Servers) > 1 {
+		warnings = append(warnings, "only the first server is used as host and basePath in Swagger 2.0")
+	}
+	if len(doc.Webhooks) > 0 {
+		warnings = append(warnings, "webhooks are not supported by Swagger 2.0")
+	}
+	doc.Operations(func(path, method string, operation *Operation) {
+		owner := method + " " + path
+		for _, param := range operation.Parameters {
+			if param != nil && param.In == ParameterInCookie {
+				warnings = append(warnings, fmt.Sprintf("cookie parameter %s of operation %s is not supported by Swagger 2.0", param.Name, owner))
+			}
+		}
+		for _, code := range utils.SortedKeys(operation.Responses) {
+			response := operation.Responses[code]
+			if response != nil && len(response.Content) > 1 {
+				warnings = append(warnings, fmt.Sprintf("response %s of operation %s has multiple content types, only one of them is kept in Swagger 2.0", code, owner))
+			}
+		}
+		for _, schema := range append(operation.RequestSchemas(), operation.ResponseSchemas()...) {
+			if hasOneOf(schema) {
+				warnings = append(warnings, fmt.Sprintf("oneOf/anyOf in operation %s is not supported by Swagger 2.0", owner))
+				break
+			}
+		}
+		if len(operation.Callbacks) > 0 {
+			warnings = append(warnings, fmt.Sprintf("callbacks of operation %s are not supported by Swagger 2.0", owner))
+		}
+	})
+	return warnings
+}
+
+func hasOneOf(schema *Schema) bool {
+	var res bool
+	WalkSchema(schema, func(schema *Schema) {
+		res = res || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0
+	})
+	return res
+}
@@ -0,0 +1,75 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_ToSwagger2(t *testing.T) {
+	pet := NewObjectSchema().WithProperty("name", &Schema{Type: "string", Nullable: true})
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Servers: Servers{{URL: "https://example.com/{basePath}", Variables: map[string]*ServerVariable{"basePath": {Default: "api"}}}},
+		Components: Components{
+			Schemas: Schemas{
+				"Pet":   pet,
+				"Shape": &Schema{OneOf: SchemaRefs{RefComponentSchemas("Pet")}},
+			},
+			SecuritySchemes: SecuritySchemes{
+				"token": &SecuritySchemeRef{Value: NewSecurityScheme().WithType("apiKey").WithIn("header").WithName("X-Token")},
+			},
+		},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Post: &Operation{
+					OperationID: "createPet",
+					Parameters: Parameters{
+						NewQueryParameter("dry").WithSchema(NewBoolSchema()),
+						NewCookieParameter("session").WithSchema(NewStringSchema()),
+					},
+					RequestBody: NewRequestBody().WithContent(NewContentWithJSONSchemaRef(RefComponentSchemas("Pet"))),
+					Responses: Responses{
+						"200": NewResponse().WithDescription("OK").
+							WithContent(NewContentWithSchemaRef(RefComponentSchemas("Pet"), []string{"application/json", "application/xml"})),
+					},
+				},
+			},
+		},
+	}
+
+	doc2, warnings, err := doc.ToSwagger2()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"oneOf/anyOf in schema Shape is not supported by Swagger 2.0",
+		"cookie parameter session of operation POST /pets is not supported by Swagger 2.0",
+		"response 200 of operation POST /pets has multiple content types, only one of them is kept in Swagger 2.0",
+	}, warnings)
+
+	data, err := json.Marshal(doc2)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"swagger": "2.0",
+		"info": {"title": "Example", "version": "1.0"},
+		"host": "example.com",
+		"basePath": "/api",
+		"schemes": ["https"],
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"name": {"type": "string", "x-nullable": true}}},
+			"Shape": {}
+		},
+		"securityDefinitions": {"token": {"type": "apiKey", "in": "header", "name": "X-Token"}},
+		"paths": {"/pets": {"post": {
+			"operationId": "createPet",
+			"consumes": ["application/json"],
+			"produces": ["application/json", "application/xml"],
+			"parameters": [
+				{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}},
+				{"in": "query", "name": "dry", "type": "boolean"}
+			],
+			"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}
+		}}}
+	}`, string(data))
+}
@@ -0,0 +1,172 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// JSONSchemaDialect31 is the default JSON Schema dialect (based on JSON Schema 2020-12) of OpenAPI 3.1 documents
+const JSONSchemaDialect31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// IsOpenAPI31 reports whether the version of doc is 3.1.x
+func (doc *T) IsOpenAPI31() bool {
+	return strings.HasPrefix(doc.OpenAPI, "3.1")
+}
+
+// To31 returns JSON compatible representation of doc in OpenAPI 3.1 shape. Schemas are converted to JSON Schema 2020-12:
+//   - references wrapped by "allOf" are unwrapped, since "$ref" may have sibling keywords in 3.1
+//   - "nullable" is replaced by "null" in "type" (or "anyOf" when the schema has no type)
+//   - boolean "exclusiveMinimum"/"exclusiveMaximum" are replaced by numeric bounds
+//   - "example" is replaced by "examples" array
+//   - enum with only one value is replaced by "const"
+//   - "format: binary/base64" of strings are replaced by "contentMediaType"/"contentEncoding"
+func (doc *T) To31() (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	if !doc.IsOpenAPI31() {
+		res["openapi"] = "3.1.0"
+	}
+	if _, ok := res["jsonSchemaDialect"]; !ok {
+		res["jsonSchemaDialect"] = JSONSchemaDialect31
+	}
+	walkJSONSchemas(res, convertSchema31)
+	return res, nil
+}
+
+// walkJSONSchemas calls convert for every schema nested in the JSON representation of document (value), from the
+// innermost to the outermost. Schemas are replaced with the return value of convert.
+func walkJSONSchemas(value interface{}, convert func(schema map[string]interface{}) interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			switch {
+			case key == "example" || key == "examples" || strings.HasPrefix(key, "x-"):
+				// literal values
+			case key == "schema":
+				value[key] = walkJSONSchema(item, convert)
+			case key == "schemas":
+				schemas, ok := item.(map[string]interface{})
+				if !ok {
+					break
+				}
+				for name, schema := range schemas {
+					schemas[name] = walkJSONSchema(schema, convert)
+				}
+			default:
+				walkJSONSchemas(item, convert)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			walkJSONSchemas(item, convert)
+		}
+	}
+}
+
+func walkJSONSchema(value interface{}, convert func(schema map[string]interface{}) interface{}) interface{} {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if item, ok := schema[key]; ok {
+			schema[key] = walkJSONSchema(item, convert)
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if items, ok := schema[key].([]interface{}); ok {
+			for i, item := range items {
+				items[i] = walkJSONSchema(item, convert)
+			}
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, item := range properties {
+			properties[name] = walkJSONSchema(item, convert)
+		}
+	}
+	return convert(schema)
+}
+
+func convertSchema31(schema map[string]interface{}) interface{} {
+	unwrapRef31(schema)
+	convertBound31(schema, "exclusiveMinimum", "minimum")
+	convertBound31(schema, "exclusiveMaximum", "maximum")
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+	if schema["type"] == "string" {
+		switch schema["format"] {
+		case "binary":
+			delete(schema, "format")
+			schema["contentMediaType"] = "application/octet-stream"
+		case "base64", "byte":
+			delete(schema, "format")
+			schema["contentEncoding"] = "base64"
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) == 1 {
+		delete(schema, "enum")
+		schema["const"] = enum[0]
+	}
+
+	if nullable, _ := schema["nullable"].(bool); !nullable {
+		delete(schema, "nullable")
+		return schema
+	}
+	delete(schema, "nullable")
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = append(enum, nil)
+	}
+	if c, ok := schema["const"]; ok {
+		delete(schema, "const")
+		schema["enum"] = []interface{}{c, nil}
+	}
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []interface{}{t, "null"}
+		return schema
+	}
+	// schemas without type (e.g. references) are combined with null schema
+	return map[string]interface{}{
+		"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+	}
+}
+
+// unwrapRef31 replaces {"allOf": [{"$ref": ...}], ...} with {"$ref": ..., ...}
+func unwrapRef31(schema map[string]interface{}) {
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok || len(allOf) != 1 {
+		return
+	}
+	ref, ok := allOf[0].(map[string]interface{})
+	if !ok || len(ref) != 1 || ref["$ref"] == nil {
+		return
+	}
+	delete(schema, "allOf")
+	schema["$ref"] = ref["$ref"]
+}
+
+// convertBound31 replaces boolean exclusive bound with numeric one
+func convertBound31(schema map[string]interface{}, exclusiveKey, boundKey string) {
+	exclusive, ok := schema[exclusiveKey].(bool)
+	if !ok {
+		return
+	}
+	delete(schema, exclusiveKey)
+	if bound, ok := schema[boundKey]; ok && exclusive {
+		delete(schema, boundKey)
+		schema[exclusiveKey] = bound
+	}
+}
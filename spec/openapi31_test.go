@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_To31(t *testing.T) {
+	min := 0.0
+	schema := NewObjectSchema().
+		WithProperty("name", &Schema{Type: "string", Nullable: true, Example: "Tom"}).
+		WithProperty("age", &Schema{Type: "integer", Min: &min, ExclusiveMin: true}).
+		WithProperty("kind", &Schema{Type: "string", Enum: []interface{}{"cat"}}).
+		WithProperty("file", &Schema{Type: "string", Format: "binary"}).
+		WithProperty("owner", &Schema{Ref: "#/components/schemas/User", Nullable: true}).
+		WithProperty("creator", &Schema{Ref: "#/components/schemas/User", ReadOnly: true})
+	doc := &T{
+		OpenAPI: "3.1.0",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Components: Components{Schemas: Schemas{
+			"Pet":  schema,
+			"User": NewObjectSchema().WithProperty("id", NewIntegerSchema()),
+		}},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Get: &Operation{
+					Responses: Responses{"default": NewResponse().WithDescription("").WithJSONSchema(&Schema{Type: "integer", Nullable: true})},
+				},
+			},
+		},
+	}
+
+	res, err := doc.To31()
+	require.NoError(t, err)
+	data, err := json.Marshal(res)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"openapi": "3.1.0",
+		"jsonSchemaDialect": "https://spec.openapis.org/oas/3.1/dialect/base",
+		"info": {"title": "Example", "version": "1.0"},
+		"components": {"schemas": {
+			"Pet": {
+				"type": "object",
+				"ext": {"type": "object"},
+				"properties": {
+					"name": {"type": ["string", "null"], "examples": ["Tom"]},
+					"age": {"type": "integer", "exclusiveMinimum": 0},
+					"kind": {"type": "string", "const": "cat"},
+					"file": {"type": "string", "contentMediaType": "application/octet-stream"},
+					"owner": {"anyOf": [{"$ref": "#/components/schemas/User"}, {"type": "null"}]},
+					"creator": {"$ref": "#/components/schemas/User", "readOnly": true}
+				}
+			},
+			"User": {"type": "object", "ext": {"type": "object"}, "properties": {"id": {"type": "integer"}}}
+		}},
+		"paths": {"/pets": {"get": {"responses": {"default": {
+			"description": "",
+			"content": {"application/json": {"schema": {"type": ["integer", "null"]}}}
+		}}}}}
+	}`, string(data))
+}
@@ -0,0 +1,200 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Overlay is OpenAPI Overlay document (https://spec.openapis.org/overlay/v1.0.0.html)
+type Overlay struct {
+	Overlay string           `json:"overlay"`
+	Info    *OverlayInfo     `json:"info,omitempty"`
+	Extends string           `json:"extends,omitempty"`
+	Actions []*OverlayAction `json:"actions"`
+}
+
+type OverlayInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OverlayAction updates or removes the nodes selected by Target
+type OverlayAction struct {
+	// JSONPath expression selecting the nodes
+	Target      string `json:"target"`
+	Description string `json:"description,omitempty"`
+	// Merged into the selected objects, or appended to the selected arrays
+	Update interface{} `json:"update,omitempty"`
+	// Removes the selected nodes
+	Remove bool `json:"remove,omitempty"`
+}
+
+// ApplyOverlay returns copy of doc with actions of overlay applied in order. An error is returned if the target of
+// any action selects nothing. doc is not modified.
+func (doc *T) ApplyOverlay(overlay *Overlay) (*T, error) {
+	return doc.transformJSON(func(root interface{}) (interface{}, error) {
+		for i, action := range overlay.Actions {
+			if action == nil {
+				continue
+			}
+			var err error
+			root, err = action.apply(root)
+			if err != nil {
+				return nil, fmt.Errorf("actions[%d]: %w", i, err)
+			}
+		}
+		return root, nil
+	})
+}
+
+func (a *OverlayAction) apply(root interface{}) (interface{}, error) {
+	path, err := compileJSONPath(a.Target)
+	if err != nil {
+		return nil, err
+	}
+	locations := path.selectLocations(root)
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("target %q matches nothing", a.Target)
+	}
+	if a.Remove {
+		return removeValuesAt(root, locations), nil
+	}
+	if a.Update == nil {
+		return root, nil
+	}
+	update, err := normalizeJSON(a.Update)
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations {
+		value, _ := valueAt(root, location)
+		switch value := value.(type) {
+		case map[string]interface{}:
+			object, ok := update.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("update of object %s must be an object", location)
+			}
+			mergeJSON(value, object)
+		case []interface{}:
+			root = setValueAt(root, location, append(value, copyJSON(update)))
+		default:
+			return nil, fmt.Errorf("target %s is neither an object nor an array", location)
+		}
+	}
+	return root, nil
+}
+
+// mergeJSON merges properties of src into dst recursively. Objects are merged, arrays are concatenated and the other
+// values are replaced
+func mergeJSON(dst, src map[string]interface{}) {
+	for key, value := range src {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if object, ok := dst[key].(map[string]interface{}); ok {
+				mergeJSON(object, value)
+				continue
+			}
+		case []interface{}:
+			if array, ok := dst[key].([]interface{}); ok {
+				dst[key] = append(array, copyJSON(value).([]interface{})...)
+				continue
+			}
+		}
+		dst[key] = copyJSON(value)
+	}
+}
+
+// ApplyMergePatch returns copy of doc with JSON Merge Patch (RFC 7386) applied. doc is not modified.
+func (doc *T) ApplyMergePatch(patch interface{}) (*T, error) {
+	return doc.transformJSON(func(root interface{}) (interface{}, error) {
+		patch, err := normalizeJSON(patch)
+		if err != nil {
+			return nil, err
+		}
+		return mergePatch(root, patch), nil
+	})
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	object, ok := patch.(map[string]interface{})
+	if !ok {
+		return copyJSON(patch)
+	}
+	res, ok := target.(map[string]interface{})
+	if !ok {
+		res = make(map[string]interface{})
+	}
+	for key, value := range object {
+		if value == nil {
+			delete(res, key)
+		} else {
+			res[key] = mergePatch(res[key], value)
+		}
+	}
+	return res
+}
+
+// transformJSON returns copy of doc transformed in JSON form. Information which is not encoded into JSON (e.g.
+// extended type info and generic types used by generators) is restored by location of schemas.
+func (doc *T) transformJSON(fn func(root interface{}) (interface{}, error)) (*T, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var root interface{}
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make(map[string]struct{})
+	if components, ok := root.(map[string]interface{})["components"].(map[string]interface{}); ok {
+		schemas, _ := components["schemas"].(map[string]interface{})
+		for key := range schemas {
+			encoded[key] = struct{}{}
+		}
+	}
+	root, err = fn(root)
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	res := &T{}
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	restoreSchemas(res, doc, encoded)
+	return res, nil
+}
+
+// normalizeJSON converts value (e.g. decoded from YAML) into the types decoded by encoding/json
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func copyJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			res[key] = copyJSON(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, item := range value {
+			res[i] = copyJSON(item)
+		}
+		return res
+	}
+	return value
+}
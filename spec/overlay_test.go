@@ -0,0 +1,134 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newOverlayTestDoc() *T {
+	status := NewStringSchema().WithEnum("on", "off")
+	status.Description = "Status"
+	status.ExtendedTypeInfo = NewExtendedEnumType(NewExtendEnumItem("On", "on", ""), NewExtendEnumItem("Off", "off", ""))
+	return &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Components: Components{
+			Schemas: Schemas{
+				"Status": status,
+				"User": NewObjectSchema().
+					WithProperty("name", NewStringSchema()).
+					WithPropertyRef("status", RefComponentSchemas("Status")).
+					WithPropertyRef("manager", &Schema{Ref: componentSchemasPrefix + "User", ReadOnly: true}),
+			},
+		},
+		Paths: Paths{
+			"/users": &PathItem{
+				Get: &Operation{
+					Tags: []string{"User"},
+					Parameters: Parameters{
+						NewQueryParameter("page").WithSchema(NewIntegerSchema()),
+						NewHeaderParameter("X-Trace").WithSchema(NewStringSchema()),
+					},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("User"))},
+				},
+			},
+		},
+	}
+}
+
+func TestT_ApplyOverlay(t *testing.T) {
+	doc := newOverlayTestDoc()
+	res, err := doc.ApplyOverlay(&Overlay{
+		Overlay: "1.0.0",
+		Actions: []*OverlayAction{
+			{Target: "$.info", Update: map[string]interface{}{"description": "Marketing description"}},
+			{Target: "$.paths['/users'].get", Update: map[string]interface{}{"deprecated": true, "tags": []interface{}{"Legacy"}}},
+			{Target: "$.paths.*.*.parameters[?(@.in == 'header')]", Remove: true},
+			{Target: "$.components.schemas.User.properties.name", Update: map[string]interface{}{"minLength": 1}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Marketing description", res.Info.Description)
+	operation := res.Paths["/users"].Get
+	require.True(t, operation.Deprecated)
+	require.Equal(t, []string{"User", "Legacy"}, operation.Tags)
+	require.Len(t, operation.Parameters, 1)
+	require.Equal(t, "page", operation.Parameters[0].Name)
+	require.Equal(t, "#/components/schemas/User", operation.Responses["200"].Content["application/json"].Schema.Ref)
+
+	require.Equal(t, uint64(1), res.Components.Schemas["User"].Properties["name"].MinLength)
+
+	// information not encoded into JSON is restored
+	require.Equal(t, "#/components/schemas/Status", res.Components.Schemas["User"].Properties["status"].Ref)
+	require.Equal(t, "Status", res.Components.Schemas["Status"].Description)
+	require.Equal(t, doc.Components.Schemas["Status"].ExtendedTypeInfo, res.Components.Schemas["Status"].ExtendedTypeInfo)
+
+	// reference with readOnly (wrapped by allOf in JSON) is kept as is
+	manager := res.Components.Schemas["User"].Properties["manager"]
+	require.Equal(t, "#/components/schemas/User", manager.Ref)
+	require.True(t, manager.ReadOnly)
+	require.Empty(t, manager.AllOf)
+
+	// doc is not modified
+	require.False(t, doc.Paths["/users"].Get.Deprecated)
+	require.Len(t, doc.Paths["/users"].Get.Parameters, 2)
+}
+
+func TestT_ApplyOverlay_Errors(t *testing.T) {
+	doc := newOverlayTestDoc()
+	tests := []struct {
+		action *OverlayAction
+		err    string
+	}{
+		{
+			action: &OverlayAction{Target: "$.paths['/nope']", Remove: true},
+			err:    `actions[1]: target "$.paths['/nope']" matches nothing`,
+		},
+		{
+			action: &OverlayAction{Target: "$.paths.*.get.parameters[5]", Update: map[string]interface{}{"required": true}},
+			err:    `actions[1]: target "$.paths.*.get.parameters[5]" matches nothing`,
+		},
+		{
+			action: &OverlayAction{Target: "$..parameters[?@.in == 'cookie']", Remove: true},
+			err:    `actions[1]: target "$..parameters[?@.in == 'cookie']" matches nothing`,
+		},
+		{
+			action: &OverlayAction{Target: "$.paths[", Remove: true},
+			err:    `actions[1]: invalid JSONPath "$.paths[": at position 8: unclosed '['`,
+		},
+		{
+			action: &OverlayAction{Target: "$.info", Update: "x"},
+			err:    `actions[1]: update of object $['info'] must be an object`,
+		},
+		{
+			action: &OverlayAction{Target: "$.info.title", Update: map[string]interface{}{"x": 1}},
+			err:    `actions[1]: target $['info']['title'] is neither an object nor an array`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action.Target, func(t *testing.T) {
+			_, err := doc.ApplyOverlay(&Overlay{Actions: []*OverlayAction{
+				{Target: "$.info", Update: map[string]interface{}{"description": "ok"}},
+				tt.action,
+			}})
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestT_ApplyMergePatch(t *testing.T) {
+	doc := newOverlayTestDoc()
+	res, err := doc.ApplyMergePatch(map[string]interface{}{
+		"info": map[string]interface{}{"description": "Marketing description", "version": nil},
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{"get": map[string]interface{}{"summary": "List users"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Marketing description", res.Info.Description)
+	require.Equal(t, "", res.Info.Version)
+	require.Equal(t, "List users", res.Paths["/users"].Get.Summary)
+	require.Len(t, res.Paths["/users"].Get.Parameters, 2)
+	require.Equal(t, "1.0", doc.Info.Version)
+}
@@ -201,6 +201,8 @@ type schemaRef struct {
 	Summary     string `json:"summary,omitempty" yaml:"summary,omitempty"`
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	Ref         string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	// kept for OpenAPI 3.1 output, in which the reference is combined with null schema
+	Nullable bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
 }
 
 func (value *Schema) Unref(doc *T) *Schema {
@@ -190,36 +190,96 @@ func (schema *Schema) WithExtendedType(t *ExtendedTypeInfo) *Schema {
 // MarshalJSON returns the JSON encoding of Schema.
 func (schema *Schema) MarshalJSON() ([]byte, error) {
 	if schema.Ref != "" {
-		return json.Marshal(schemaRef{
+		if wrapper := schema.refWrapper(); wrapper != nil {
+			return wrapper.MarshalJSON()
+		}
+		ref := schemaRef{
 			Summary:     schema.Summary,
 			Description: schema.Description,
 			Ref:         schema.Ref,
-		})
-	}
-
-	schema = schema.Clone()
-	ext := schema.ExtendedTypeInfo
-	if ext != nil && len(ext.EnumItems) > 0 {
-		desc := schema.Description
-		if desc != "" {
-			desc += "\n\n"
+			Nullable:    schema.Nullable,
+		}
+		if len(schema.Extensions) == 0 {
+			return json.Marshal(ref)
+		}
+		// vendor extensions declared on properties are kept as siblings of $ref
+		res := make(map[string]interface{}, len(schema.Extensions)+3)
+		for name, value := range schema.Extensions {
+			res[name] = value
 		}
-		desc += "<table><tr><th>Value</th><th>Key</th><th>Description</th></tr>"
-		for _, item := range ext.EnumItems {
-			desc += fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td></tr>", cast.ToString(item), item.Key, item.Description)
+		data, err := json.Marshal(ref)
+		if err != nil {
+			return nil, err
 		}
-		desc += "</table>"
-		schema.Description = desc
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		for name, value := range fields {
+			res[name] = value
+		}
+		return json.Marshal(res)
 	}
+
+	schema = schema.Clone()
+	schema.Description = schema.encodedDescription()
 	if !utils.Debug() {
 		schema.ExtendedTypeInfo = nil
 	}
 	return jsoninfo.MarshalStrictStruct(schema)
 }
 
+// refWrapper returns {"allOf": [{"$ref": ...}], ...} for the reference which has keywords declared on a property
+// (e.g. readOnly or @min), because siblings of "$ref" are ignored in OpenAPI 3.0. Summary, description and nullable
+// are kept next to "$ref". Returns nil if the reference can be encoded as is.
+func (schema *Schema) refWrapper() *Schema {
+	if !schema.ReadOnly && !schema.WriteOnly && !schema.Deprecated &&
+		schema.Default == nil && schema.Example == nil && len(schema.Enum) == 0 &&
+		schema.Format == "" && schema.Pattern == "" && schema.Min == nil && schema.Max == nil &&
+		schema.MinLength == 0 && schema.MaxLength == nil {
+		return nil
+	}
+
+	res := schema.Clone()
+	res.Ref = ""
+	res.AllOf = append(SchemaRefs{{Ref: schema.Ref}}, res.AllOf...)
+	return res
+}
+
+// encodedDescription returns description of schema in JSON, which includes the table of enum items
+func (schema *Schema) encodedDescription() string {
+	ext := schema.ExtendedTypeInfo
+	if ext == nil || len(ext.EnumItems) == 0 {
+		return schema.Description
+	}
+	desc := schema.Description
+	if desc != "" {
+		desc += "\n\n"
+	}
+	desc += "<table><tr><th>Value</th><th>Key</th><th>Description</th></tr>"
+	for _, item := range ext.EnumItems {
+		desc += fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td></tr>", cast.ToString(item), item.Key, item.Description)
+	}
+	desc += "</table>"
+	return desc
+}
+
 // UnmarshalJSON sets Schema to a copy of data.
 func (schema *Schema) UnmarshalJSON(data []byte) error {
-	return jsoninfo.UnmarshalStrictStruct(data, schema)
+	err := jsoninfo.UnmarshalStrictStruct(data, schema)
+	if err != nil {
+		return err
+	}
+	// references are encoded as "$ref" by MarshalJSON
+	var ref schemaRef
+	err = json.Unmarshal(data, &ref)
+	if err != nil {
+		return err
+	}
+	if ref.Ref != "" {
+		schema.Ref = ref.Ref
+	}
+	return nil
 }
 
 // JSONLookup implements github.com/go-openapi/jsonpointer#JSONPointable
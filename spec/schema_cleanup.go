@@ -0,0 +1,163 @@
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/chenwei67/eapi/utils"
+	"github.com/samber/lo"
+)
+
+// PruneSchemas removes the component schemas which are not (indirectly) referenced by operations, callbacks,
+// webhooks or the other components. Returns keys of the removed schemas in order.
+func (doc *T) PruneSchemas() []string {
+	referenced := doc.ReferencedSchemas(doc.usedSchemas()...)
+	var removed []string
+	for _, key := range utils.SortedKeys(doc.Components.Schemas) {
+		if _, ok := referenced[key]; !ok {
+			removed = append(removed, key)
+			delete(doc.Components.Schemas, key)
+		}
+	}
+	return removed
+}
+
+// DeduplicateSchemas merges the component schemas which are structurally identical (title and Go source extensions
+// are ignored) into the one with the smallest key, and rewrites the references to them. Instances of the same generic
+// type are merged into a neutral name instead, in which the differing type arguments are replaced with "any"
+// (e.g. "Resp[User]" and "Resp[Member]" are merged into "Resp[any]"). Schemas which become identical after merging
+// (e.g. the ones referencing merged schemas) are merged as well. Returns merged key => kept key.
+func (doc *T) DeduplicateSchemas() map[string]string {
+	merged := make(map[string]string)
+	for {
+		var groups [][]string
+		index := make(map[string]int) // signature => index of group
+		for _, key := range utils.SortedKeys(doc.Components.Schemas) {
+			schema := doc.Components.Schemas[key]
+			if schema != nil && schema.ExtendedTypeInfo != nil && len(schema.ExtendedTypeInfo.TypeParams) > 0 {
+				continue // definition of generic type
+			}
+			signature, ok := schemaSignature(schema)
+			if !ok {
+				continue
+			}
+			if i, ok := index[signature]; ok {
+				groups[i] = append(groups[i], key)
+			} else {
+				index[signature] = len(groups)
+				groups = append(groups, []string{key})
+			}
+		}
+		names := make(map[string]string)
+		for _, group := range groups {
+			if len(group) <= 1 {
+				continue
+			}
+			name := doc.mergedSchemaName(group)
+			for _, key := range group {
+				if key != name {
+					names[key] = name
+				}
+			}
+		}
+		if len(names) == 0 {
+			return merged
+		}
+
+		rename := func(key string) (string, bool) {
+			name, ok := names[key]
+			return name, ok
+		}
+		for _, root := range doc.rootSchemas() {
+			RewriteSchemaRefs(root, rename)
+		}
+		for key, name := range merged {
+			if renamed, ok := names[name]; ok {
+				merged[key] = renamed
+			}
+		}
+		// the schema with the smallest key of each group is kept
+		schemas := make(Schemas, len(doc.Components.Schemas))
+		for _, key := range utils.SortedKeys(doc.Components.Schemas) {
+			schema := doc.Components.Schemas[key]
+			name, ok := names[key]
+			if !ok {
+				schemas[key] = schema
+				continue
+			}
+			merged[key] = name
+			if _, exists := schemas[name]; !exists && doc.Components.Schemas[name] == nil {
+				schema.Key = name
+				schemas[name] = schema
+			}
+		}
+		doc.Components.Schemas = schemas
+	}
+}
+
+// mergedSchemaName returns key of the schema merged from the group of structurally identical schemas (in order of
+// keys). It is the smallest key unless all of them are instances of the same generic type.
+func (doc *T) mergedSchemaName(group []string) string {
+	var base string
+	var args []string
+	for i, key := range group {
+		schema := doc.Components.Schemas[key]
+		ext := schema.ExtendedTypeInfo
+		if !schema.SpecializedFromGeneric || ext == nil || ext.Type != ExtendedTypeSpecific || ext.SpecificType == nil {
+			return group[0]
+		}
+		if i == 0 {
+			base = ext.SpecificType.Type.GetKey()
+			for _, arg := range ext.SpecificType.Args {
+				args = append(args, arg.GetKey())
+			}
+			continue
+		}
+		if ext.SpecificType.Type.GetKey() != base || len(ext.SpecificType.Args) != len(args) {
+			return group[0]
+		}
+		for j, arg := range ext.SpecificType.Args {
+			if arg.GetKey() != args[j] {
+				args[j] = "any"
+			}
+		}
+	}
+	name := base + "[" + strings.Join(args, ",") + "]"
+	// the name is used by another schema. e.g. instance of generic type with "any" argument
+	if _, exists := doc.Components.Schemas[name]; exists && !lo.Contains(group, name) {
+		return group[0]
+	}
+	return name
+}
+
+// schemaSignature returns JSON encoding of schema without title and Go source extensions, which is the same for
+// structurally identical schemas
+func schemaSignature(schema *Schema) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+	// extended type info is encoded in debug mode
+	schema = schema.Clone()
+	WalkSchema(schema, func(schema *Schema) { schema.ExtendedTypeInfo = nil })
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", false
+	}
+	var value map[string]interface{}
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return "", false
+	}
+	delete(value, "title")
+	for name := range value {
+		if isSourceExtension(name) {
+			delete(value, name)
+		}
+	}
+	// map keys are sorted by encoding/json
+	data, err = json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
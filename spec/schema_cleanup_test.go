@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/chenwei67/eapi/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_PruneSchemas(t *testing.T) {
+	doc := &T{
+		Components: Components{
+			Schemas: Schemas{
+				"User":    NewObjectSchema().WithPropertyRef("profile", RefComponentSchemas("Profile")),
+				"Profile": NewObjectSchema(),
+				"Pet":     NewObjectSchema(),
+				"Cat":     &Schema{Discriminator: &Discriminator{PropertyName: "kind", Mapping: map[string]string{"cat": "#/components/schemas/Pet"}}},
+				"Unused":  NewObjectSchema().WithPropertyRef("cat", RefComponentSchemas("Cat")),
+			},
+		},
+		Paths: Paths{
+			"/users": &PathItem{
+				Get: &Operation{
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("User"))},
+				},
+			},
+		},
+	}
+	require.Equal(t, []string{"Cat", "Pet", "Unused"}, doc.PruneSchemas())
+	require.ElementsMatch(t, []string{"Profile", "User"}, utils.SortedKeys(doc.Components.Schemas))
+}
+
+func TestT_DeduplicateSchemas(t *testing.T) {
+	doc := &T{
+		Components: Components{
+			Schemas: Schemas{
+				"a.Address": NewObjectSchema().WithProperty("city", NewStringSchema()),
+				"b.Address": NewObjectSchema().WithProperty("city", NewStringSchema()),
+				// identical after merging b.Address into a.Address
+				"a.User": NewObjectSchema().WithPropertyRef("address", RefComponentSchemas("a.Address")),
+				"b.User": NewObjectSchema().WithPropertyRef("address", RefComponentSchemas("b.Address")),
+				"c.User": NewObjectSchema().WithPropertyRef("address", RefComponentSchemas("a.Address")).
+					WithProperty("age", NewIntegerSchema()),
+			},
+		},
+		Paths: Paths{
+			"/users": &PathItem{
+				Get: &Operation{
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("b.User"))},
+				},
+			},
+		},
+	}
+	doc.Components.Schemas["a.Address"].Title = "AAddress"
+	doc.Components.Schemas["b.Address"].Title = "BAddress"
+	doc.Components.Schemas["a.Address"].Extensions = map[string]interface{}{ExtensionGoType: "a.Address"}
+	doc.Components.Schemas["b.Address"].Extensions = map[string]interface{}{ExtensionGoType: "b.Address"}
+
+	merged := doc.DeduplicateSchemas()
+	require.Equal(t, map[string]string{"b.Address": "a.Address", "b.User": "a.User"}, merged)
+	require.ElementsMatch(t, []string{"a.Address", "a.User", "c.User"}, utils.SortedKeys(doc.Components.Schemas))
+	require.Equal(t, "#/components/schemas/a.User", doc.Paths["/users"].Get.Responses["200"].Content["application/json"].Schema.Ref)
+}
@@ -0,0 +1,157 @@
+package spec
+
+import "github.com/chenwei67/eapi/utils"
+
+const (
+	inputSchemaSuffix  = "Input"
+	outputSchemaSuffix = "Output"
+)
+
+// SplitSchemasByDirection emits distinct "XxxInput" and "XxxOutput" component schemas for the component
+// which is used by both request and response and contains readOnly/writeOnly properties.
+// Properties marked as readOnly are removed from the input schema, and writeOnly ones are removed from
+// the output schema.
+func (doc *T) SplitSchemasByDirection() *T {
+	newDirectionSplitter(doc).split()
+	return doc
+}
+
+type directionSplitter struct {
+	doc         *T
+	directional map[string]bool
+}
+
+func newDirectionSplitter(doc *T) *directionSplitter {
+	return &directionSplitter{doc: doc, directional: make(map[string]bool)}
+}
+
+func (s *directionSplitter) split() {
+	var requestRoots, responseRoots []*Schema
+	s.doc.Operations(func(path, method string, operation *Operation) {
+		requestRoots = append(requestRoots, operation.RequestSchemas()...)
+		responseRoots = append(responseRoots, operation.ResponseSchemas()...)
+	})
+	// requests of callbacks and webhooks are sent by the server
+	s.doc.OutboundOperations(func(key, method string, operation *Operation) {
+		requestRoots = append(requestRoots, operation.ResponseSchemas()...)
+		responseRoots = append(responseRoots, operation.RequestSchemas()...)
+	})
+	inputKeys := s.doc.ReferencedSchemas(requestRoots...)
+	outputKeys := s.doc.ReferencedSchemas(responseRoots...)
+
+	var splitKeys = make(map[string]struct{})
+	for _, key := range utils.SortedKeys(inputKeys) {
+		if _, ok := outputKeys[key]; ok && s.isDirectional(key, make(map[string]struct{})) {
+			splitKeys[key] = struct{}{}
+		}
+	}
+	if len(splitKeys) == 0 {
+		return
+	}
+
+	for _, key := range utils.SortedKeys(splitKeys) {
+		schema := s.doc.Components.Schemas[key]
+		s.doc.Components.Schemas[key+inputSchemaSuffix] = s.variant(schema, key+inputSchemaSuffix, inputSchemaSuffix, splitKeys)
+		s.doc.Components.Schemas[key+outputSchemaSuffix] = s.variant(schema, key+outputSchemaSuffix, outputSchemaSuffix, splitKeys)
+	}
+
+	// components which are used in only one direction
+	for _, key := range utils.SortedKeys(inputKeys) {
+		if _, ok := outputKeys[key]; !ok {
+			s.rewriteRefs(s.doc.Components.Schemas[key], inputSchemaSuffix, splitKeys)
+		}
+	}
+	for _, key := range utils.SortedKeys(outputKeys) {
+		if _, ok := inputKeys[key]; !ok {
+			s.rewriteRefs(s.doc.Components.Schemas[key], outputSchemaSuffix, splitKeys)
+		}
+	}
+	for _, root := range requestRoots {
+		s.rewriteRefs(root, inputSchemaSuffix, splitKeys)
+	}
+	for _, root := range responseRoots {
+		s.rewriteRefs(root, outputSchemaSuffix, splitKeys)
+	}
+
+	// remove the original schemas which are no longer referenced
+	var roots []*Schema
+	for _, key := range utils.SortedKeys(s.doc.Components.Schemas) {
+		if _, ok := splitKeys[key]; !ok {
+			roots = append(roots, s.doc.Components.Schemas[key])
+		}
+	}
+	referenced := s.doc.ReferencedSchemas(append(roots, append(requestRoots, responseRoots...)...)...)
+	for key := range splitKeys {
+		if _, ok := referenced[key]; !ok {
+			delete(s.doc.Components.Schemas, key)
+		}
+	}
+}
+
+// isDirectional reports whether the component schema contains readOnly/writeOnly properties (recursively)
+func (s *directionSplitter) isDirectional(key string, visiting map[string]struct{}) bool {
+	if res, ok := s.directional[key]; ok {
+		return res
+	}
+	if _, ok := visiting[key]; ok {
+		return false
+	}
+	visiting[key] = struct{}{}
+
+	var res bool
+	WalkSchema(s.doc.Components.Schemas[key], func(schema *Schema) {
+		if res {
+			return
+		}
+		if schema.ReadOnly || schema.WriteOnly {
+			res = true
+			return
+		}
+		if ref := ComponentSchemaKey(schema.Ref); ref != "" && s.isDirectional(ref, visiting) {
+			res = true
+		}
+	})
+	s.directional[key] = res
+	return res
+}
+
+func (s *directionSplitter) variant(schema *Schema, key, suffix string, splitKeys map[string]struct{}) *Schema {
+	res := schema.Clone()
+	res.Key = key
+	if res.Title != "" {
+		res.Title += suffix
+	}
+	WalkSchema(res, func(schema *Schema) {
+		if len(schema.Properties) == 0 {
+			return
+		}
+		for name, property := range schema.Properties {
+			if property == nil {
+				continue
+			}
+			if suffix == inputSchemaSuffix && property.ReadOnly || suffix == outputSchemaSuffix && property.WriteOnly {
+				delete(schema.Properties, name)
+				schema.Required = removeString(schema.Required, name)
+			}
+		}
+	})
+	s.rewriteRefs(res, suffix, splitKeys)
+	return res
+}
+
+func (s *directionSplitter) rewriteRefs(schema *Schema, suffix string, splitKeys map[string]struct{}) {
+	RewriteSchemaRefs(schema, func(key string) (string, bool) {
+		_, ok := splitKeys[key]
+		return key + suffix, ok
+	})
+}
+
+func removeString(values []string, value string) []string {
+	var res []string
+	for _, item := range values {
+		if item != value {
+			res = append(res, item)
+		}
+	}
+	return res
+}
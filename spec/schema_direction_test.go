@@ -0,0 +1,123 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_SplitSchemasByDirection(t *testing.T) {
+	user := NewObjectSchema().
+		WithProperty("id", &Schema{Type: "integer", ReadOnly: true}).
+		WithProperty("name", NewStringSchema()).
+		WithProperty("password", &Schema{Type: "string", WriteOnly: true}).
+		WithPropertyRef("profile", RefComponentSchemas("Profile"))
+	user.Title = "User"
+	user.Required = []string{"id", "name", "password"}
+
+	doc := &T{
+		Components: Components{Schemas: Schemas{
+			"User":    user,
+			"Profile": NewObjectSchema().WithProperty("bio", NewStringSchema()),
+			"Page":    NewObjectSchema().WithItems(RefComponentSchemas("User")),
+		}},
+		Paths: Paths{
+			"/users": &PathItem{
+				Post: &Operation{
+					RequestBody: NewRequestBody().WithContent(NewContentWithJSONSchemaRef(RefComponentSchemas("User"))),
+					Responses:   Responses{"200": NewResponse().WithJSONSchemaRef(RefComponentSchemas("User"))},
+				},
+				Get: &Operation{
+					Responses: Responses{"200": NewResponse().WithJSONSchemaRef(RefComponentSchemas("Page"))},
+				},
+			},
+		},
+	}
+	doc.SplitSchemasByDirection()
+
+	schemas := doc.Components.Schemas
+	require.NotContains(t, schemas, "User")
+	require.Contains(t, schemas, "Profile")
+
+	input := schemas["UserInput"]
+	require.NotNil(t, input)
+	require.Equal(t, "UserInput", input.Title)
+	require.NotContains(t, input.Properties, "id")
+	require.Contains(t, input.Properties, "password")
+	require.Equal(t, []string{"name", "password"}, input.Required)
+
+	output := schemas["UserOutput"]
+	require.NotNil(t, output)
+	require.Contains(t, output.Properties, "id")
+	require.NotContains(t, output.Properties, "password")
+	require.Equal(t, []string{"id", "name"}, output.Required)
+	require.Equal(t, componentSchemasPrefix+"Profile", output.Properties["profile"].Ref)
+
+	post := doc.Paths["/users"].Post
+	require.Equal(t, componentSchemasPrefix+"UserInput", post.RequestBody.Content.Get("application/json").Schema.Ref)
+	require.Equal(t, componentSchemasPrefix+"UserOutput", post.Responses["200"].Content.Get("application/json").Schema.Ref)
+	require.Equal(t, componentSchemasPrefix+"UserOutput", schemas["Page"].Items.Ref)
+}
+
+func TestT_SplitSchemasByDirection_Outbound(t *testing.T) {
+	user := NewObjectSchema().
+		WithProperty("id", &Schema{Type: "integer", ReadOnly: true}).
+		WithProperty("password", &Schema{Type: "string", WriteOnly: true})
+	event := NewObjectSchema().
+		WithProperty("id", &Schema{Type: "integer", ReadOnly: true}).
+		WithProperty("secret", &Schema{Type: "string", WriteOnly: true})
+
+	webhook := &Operation{
+		RequestBody: NewRequestBody().WithContent(NewContentWithJSONSchemaRef(RefComponentSchemas("Event"))),
+		Responses:   Responses{"200": NewResponse().WithDescription("OK")},
+	}
+	doc := &T{
+		Components: Components{Schemas: Schemas{"User": user, "Event": event}},
+		Paths: Paths{
+			"/users": &PathItem{
+				Post: &Operation{
+					RequestBody: NewRequestBody().WithContent(NewContentWithJSONSchemaRef(RefComponentSchemas("User"))),
+					Responses:   Responses{"200": NewResponse().WithDescription("OK")},
+					Callbacks: Callbacks{"created": &CallbackRef{Value: &Callback{
+						"{$request.body#/url}": &PathItem{Post: &Operation{
+							RequestBody: NewRequestBody().WithContent(NewContentWithJSONSchemaRef(RefComponentSchemas("User"))),
+						}},
+					}}},
+				},
+			},
+		},
+		Webhooks: map[string]*PathItem{"event": {Post: webhook}},
+	}
+	doc.SplitSchemasByDirection()
+
+	schemas := doc.Components.Schemas
+	// requests of callbacks and webhooks are sent by the server
+	require.Contains(t, schemas, "UserInput")
+	require.Contains(t, schemas, "UserOutput")
+	require.Contains(t, schemas, "Event")
+	require.NotContains(t, schemas, "EventInput")
+
+	callback := (*doc.Paths["/users"].Post.Callbacks["created"].Value)["{$request.body#/url}"].Post
+	require.Equal(t, componentSchemasPrefix+"UserOutput", callback.RequestBody.Content.Get("application/json").Schema.Ref)
+}
+
+func TestSchema_MarshalJSON_RefWithDirection(t *testing.T) {
+	schema := NewObjectSchema().
+		WithProperty("creator", &Schema{Ref: componentSchemasPrefix + "User", Description: "Creator", ReadOnly: true}).
+		WithProperty("password", &Schema{Ref: componentSchemasPrefix + "Password", WriteOnly: true}).
+		WithProperty("profile", &Schema{Ref: componentSchemasPrefix + "Profile", Description: "Profile"}).
+		WithProperty("manager", &Schema{Ref: componentSchemasPrefix + "User", Nullable: true})
+	schema.ExtendedTypeInfo = nil
+
+	data, err := schema.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"creator": {"allOf": [{"$ref": "#/components/schemas/User"}], "description": "Creator", "readOnly": true},
+			"password": {"allOf": [{"$ref": "#/components/schemas/Password"}], "writeOnly": true},
+			"profile": {"$ref": "#/components/schemas/Profile", "description": "Profile"},
+			"manager": {"$ref": "#/components/schemas/User", "nullable": true}
+		}
+	}`, string(data))
+}
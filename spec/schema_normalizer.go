@@ -41,6 +41,9 @@ func (s *schemaNormalizer) normalize() *T {
 		s.processPathItem(pathItem)
 		// normalize: 完成处理路径
 	}
+	for _, pathItem := range s.doc.Webhooks {
+		s.processPathItem(pathItem)
+	}
 
 	// normalize: 规范化处理完成
 	return s.doc
@@ -186,11 +189,22 @@ func (s *schemaNormalizer) processOperation(op *Operation) {
 	} else {
 		// processOperation: RequestBody为nil或有引用，跳过处理
 	}
+
+	// processOperation: 处理Callbacks
+	for _, callback := range op.Callbacks {
+		if callback == nil || callback.Value == nil {
+			continue
+		}
+		for _, item := range *callback.Value {
+			s.processPathItem(item)
+		}
+	}
 	
 	// processOperation: Operation处理完成
 }
 
 func (s *schemaNormalizer) process(ref *Schema, args []*Schema) *Schema {
+	args = s.processArgs(args)
 	schemaRef := Unref(s.doc, ref)
 	res := schemaRef.Clone()
 	res.SpecializedFromGeneric = true
@@ -235,6 +249,21 @@ func (s *schemaNormalizer) process(ref *Schema, args []*Schema) *Schema {
 	return res
 }
 
+// processArgs 将嵌套的泛型实例参数(如 Page[Resp[User]] 中的 Resp[User])替换为实例化后的 schema
+func (s *schemaNormalizer) processArgs(args []*Schema) []*Schema {
+	res := make([]*Schema, 0, len(args))
+	for _, arg := range args {
+		if arg != nil && arg.Ref == "" {
+			ext := arg.ExtendedTypeInfo
+			if ext != nil && ext.Type == ExtendedTypeSpecific {
+				arg = s.process(ext.SpecificType.Type, ext.SpecificType.Args)
+			}
+		}
+		res = append(res, arg)
+	}
+	return res
+}
+
 func (s *schemaNormalizer) mergeArgs(args []*Schema, args2 []*Schema) []*Schema {
 	res := make([]*Schema, 0, len(args))
 	for _, _arg := range args {
@@ -242,6 +271,9 @@ func (s *schemaNormalizer) mergeArgs(args []*Schema, args2 []*Schema) []*Schema
 		ext := arg.ExtendedTypeInfo
 		if ext != nil && ext.Type == ExtendedTypeParam {
 			arg = args2[ext.TypeParam.Index]
+		} else if arg.Ref == "" && ext != nil && ext.Type == ExtendedTypeSpecific {
+			// 嵌套的泛型参数, 如 Resp[Pair[T, int]]
+			arg = arg.Clone().WithExtendedType(NewSpecificExtendType(ext.SpecificType.Type, s.mergeArgs(ext.SpecificType.Args, args2)...))
 		}
 		res = append(res, arg)
 	}
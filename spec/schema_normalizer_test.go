@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newGenericSchema(title string, schema *Schema, params ...*TypeParam) *Schema {
+	schema.Title = title
+	schema.ExtendedTypeInfo = &ExtendedTypeInfo{Type: ExtendedTypeObject, TypeParams: params}
+	return schema
+}
+
+func newSpecificSchema(key string, args ...*Schema) *Schema {
+	return NewSchema().WithExtendedType(NewSpecificExtendType(RefComponentSchemas(key), args...))
+}
+
+func TestT_Specialize(t *testing.T) {
+	param := &TypeParam{Index: 0, Name: "T"}
+	doc := &T{
+		Components: Components{Schemas: Schemas{
+			"pkg.User":   NewObjectSchema().WithProperty("name", NewStringSchema()),
+			"pkg.Member": NewObjectSchema().WithProperty("id", NewStringSchema()),
+			"pkg.Resp":   newGenericSchema("Resp", NewObjectSchema().WithProperty("data", NewTypeParamSchema(param)), param),
+			"pkg.Page":   newGenericSchema("Page", NewObjectSchema().WithProperty("items", NewArraySchema(NewTypeParamSchema(param))), param),
+			"pkg.Empty":  newGenericSchema("Empty", NewObjectSchema().WithProperty("code", NewIntegerSchema()), param),
+		}},
+		Paths: Paths{
+			"/page": &PathItem{Get: &Operation{Responses: Responses{
+				"200": NewResponse().WithJSONSchema(newSpecificSchema("pkg.Page", newSpecificSchema("pkg.Resp", RefComponentSchemas("pkg.User")))),
+			}}},
+			"/users": &PathItem{Get: &Operation{Responses: Responses{
+				"200": NewResponse().WithJSONSchema(newSpecificSchema("pkg.Empty", RefComponentSchemas("pkg.User"))),
+			}}},
+			"/members": &PathItem{Get: &Operation{Responses: Responses{
+				"200": NewResponse().WithJSONSchema(newSpecificSchema("pkg.Empty", RefComponentSchemas("pkg.Member"))),
+			}}},
+		},
+	}
+	doc.Specialize()
+	schemas := doc.Components.Schemas
+
+	// nested generic type
+	page := doc.Paths["/page"].Get.Responses["200"].Content.Get("application/json").Schema
+	require.Equal(t, componentSchemasPrefix+"pkg.Page[pkg.Resp[pkg.User]]", page.Ref)
+	items := schemas["pkg.Page[pkg.Resp[pkg.User]]"].Properties["items"].Items
+	require.Equal(t, componentSchemasPrefix+"pkg.Resp[pkg.User]", items.Ref)
+	require.Equal(t, componentSchemasPrefix+"pkg.User", schemas["pkg.Resp[pkg.User]"].Properties["data"].Ref)
+
+	// structurally identical specializations are kept unless DeduplicateSchemas is called
+	users := doc.Paths["/users"].Get.Responses["200"].Content.Get("application/json").Schema
+	members := doc.Paths["/members"].Get.Responses["200"].Content.Get("application/json").Schema
+	require.Equal(t, componentSchemasPrefix+"pkg.Empty[pkg.User]", users.Ref)
+	require.Equal(t, componentSchemasPrefix+"pkg.Empty[pkg.Member]", members.Ref)
+
+	merged := doc.DeduplicateSchemas()
+	require.Equal(t, map[string]string{"pkg.Empty[pkg.User]": "pkg.Empty[any]", "pkg.Empty[pkg.Member]": "pkg.Empty[any]"}, merged)
+	require.Equal(t, componentSchemasPrefix+"pkg.Empty[any]", users.Ref)
+	require.Equal(t, componentSchemasPrefix+"pkg.Empty[any]", members.Ref)
+	require.Contains(t, doc.Components.Schemas, "pkg.Empty[any]")
+	require.NotContains(t, doc.Components.Schemas, "pkg.Empty[pkg.User]")
+	require.NotContains(t, doc.Components.Schemas, "pkg.Empty[pkg.Member]")
+}
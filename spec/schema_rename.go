@@ -0,0 +1,79 @@
+package spec
+
+import "github.com/chenwei67/eapi/utils"
+
+// RenameSchemas renames component schemas according to names (old key -> new key)
+// and rewrites all the references to them.
+func (doc *T) RenameSchemas(names map[string]string) *T {
+	if len(names) == 0 {
+		return doc
+	}
+
+	rename := func(key string) (string, bool) {
+		name, ok := names[key]
+		return name, ok
+	}
+	for _, root := range doc.rootSchemas() {
+		RewriteSchemaRefs(root, rename)
+		WalkSchema(root, func(schema *Schema) {
+			if name, ok := names[schema.Key]; ok {
+				schema.Key = name
+			}
+		})
+	}
+
+	schemas := make(Schemas, len(doc.Components.Schemas))
+	for key, schema := range doc.Components.Schemas {
+		if name, ok := names[key]; ok {
+			key = name
+		}
+		schemas[key] = schema
+	}
+	doc.Components.Schemas = schemas
+	return doc
+}
+
+// rootSchemas returns all the schemas which are not nested in other schemas
+func (doc *T) rootSchemas() []*Schema {
+	var res []*Schema
+	for _, key := range utils.SortedKeys(doc.Components.Schemas) {
+		res = append(res, doc.Components.Schemas[key])
+	}
+	return append(res, doc.usedSchemas()...)
+}
+
+// usedSchemas returns the schemas used by operations and components other than component schemas
+func (doc *T) usedSchemas() []*Schema {
+	var res []*Schema
+	for _, key := range utils.SortedKeys(doc.Components.Parameters) {
+		if param := doc.Components.Parameters[key]; param != nil {
+			res = append(res, param.Schema)
+			res = append(res, param.Content.schemas()...)
+		}
+	}
+	for _, key := range utils.SortedKeys(doc.Components.Headers) {
+		if header := doc.Components.Headers[key]; header != nil && header.Value != nil {
+			res = append(res, header.Value.Schema)
+			res = append(res, header.Value.Content.schemas()...)
+		}
+	}
+	for _, key := range utils.SortedKeys(doc.Components.RequestBodies) {
+		if requestBody := doc.Components.RequestBodies[key]; requestBody != nil {
+			res = append(res, requestBody.Content.schemas()...)
+		}
+	}
+	for _, key := range utils.SortedKeys(doc.Components.Responses) {
+		if response := doc.Components.Responses[key]; response != nil {
+			res = append(res, response.Content.schemas()...)
+		}
+	}
+	doc.Operations(func(path, method string, operation *Operation) {
+		res = append(res, operation.RequestSchemas()...)
+		res = append(res, operation.ResponseSchemas()...)
+	})
+	doc.OutboundOperations(func(key, method string, operation *Operation) {
+		res = append(res, operation.RequestSchemas()...)
+		res = append(res, operation.ResponseSchemas()...)
+	})
+	return res
+}
@@ -0,0 +1,37 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestT_RenameSchemas(t *testing.T) {
+	pet := &Schema{
+		OneOf:         []*Schema{RefComponentSchemas("a_model.Cat"), RefComponentSchemas("b_model.Cat")},
+		Discriminator: &Discriminator{PropertyName: "type", Mapping: map[string]string{"cat": "#/components/schemas/a_model.Cat"}},
+	}
+	doc := &T{
+		Components: Components{Schemas: Schemas{
+			"a_model.Cat": {Key: "a_model.Cat", Type: TypeObject},
+			"b_model.Cat": {Key: "b_model.Cat", Type: TypeObject},
+			"a_model.Pet": pet,
+		}},
+		Paths: Paths{
+			"/cats": &PathItem{
+				Get: &Operation{Responses: Responses{"200": NewResponse().WithJSONSchemaRef(RefComponentSchemas("b_model.Cat"))}},
+			},
+		},
+	}
+	doc.RenameSchemas(map[string]string{"a_model.Cat": "Cat", "b_model.Cat": "Cat2"})
+
+	schemas := doc.Components.Schemas
+	require.Len(t, schemas, 3)
+	require.Equal(t, "Cat", schemas["Cat"].Key)
+	require.Equal(t, "Cat2", schemas["Cat2"].Key)
+	require.Equal(t, componentSchemasPrefix+"Cat", pet.OneOf[0].Ref)
+	require.Equal(t, componentSchemasPrefix+"Cat2", pet.OneOf[1].Ref)
+	require.Equal(t, componentSchemasPrefix+"Cat", pet.Discriminator.Mapping["cat"])
+	response := doc.Paths["/cats"].Get.Responses["200"]
+	require.Equal(t, componentSchemasPrefix+"Cat2", response.Content.Get("application/json").Schema.Ref)
+}
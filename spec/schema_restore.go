@@ -0,0 +1,183 @@
+package spec
+
+import "strconv"
+
+// restoreSchemas copies the information which is not encoded into JSON (extended type info, key etc.) from the
+// schemas of src to the schemas of dst at the same location. dst is decoded from JSON encoding of (transformed) src,
+// in which only the component schemas in encoded are present. The other ones (generic types) are copied to dst.
+func restoreSchemas(dst, src *T, encoded map[string]struct{}) {
+	for key, schema := range src.Components.Schemas {
+		if _, ok := encoded[key]; ok {
+			continue
+		}
+		if dst.Components.Schemas == nil {
+			dst.Components.Schemas = make(Schemas)
+		}
+		dst.Components.Schemas[key] = schema
+	}
+	schemas := schemaLocations(src)
+	for location, schema := range schemaLocations(dst) {
+		if original, ok := schemas[location]; ok {
+			schema.restoreFrom(original)
+		}
+	}
+}
+
+func (schema *Schema) restoreFrom(src *Schema) {
+	// references with keywords next to them are wrapped by allOf in JSON (see refWrapper)
+	if src.Ref != "" && schema.Ref == "" && len(schema.AllOf) == len(src.AllOf)+1 && schema.AllOf[0].Ref == src.Ref {
+		schema.Ref = src.Ref
+		schema.AllOf = schema.AllOf[1:]
+		if len(schema.AllOf) == 0 {
+			schema.AllOf = nil
+		}
+	}
+	if schema.Description == src.encodedDescription() {
+		schema.Description = src.Description
+	}
+	if src.ExtendedTypeInfo != nil {
+		schema.ExtendedTypeInfo = src.ExtendedTypeInfo
+	}
+	schema.Key = src.Key
+	schema.SpecializedFromGeneric = src.SpecializedFromGeneric
+}
+
+// schemaLocations returns all the schemas of doc (except the ones in examples) by their locations. Locations are
+// similar to JSON pointers, but parameters are located by "in:name" instead of index.
+func schemaLocations(doc *T) map[string]*Schema {
+	l := schemaLocator{res: make(map[string]*Schema), visiting: make(map[*Schema]struct{})}
+	for key, schema := range doc.Components.Schemas {
+		l.schema("/components/schemas/"+key, schema)
+	}
+	for key, parameter := range doc.Components.Parameters {
+		if parameter != nil {
+			l.parameter("/components/parameters/"+key, parameter)
+		}
+	}
+	for key, header := range doc.Components.Headers {
+		if header != nil && header.Value != nil {
+			l.header("/components/headers/"+key, header.Value)
+		}
+	}
+	for key, requestBody := range doc.Components.RequestBodies {
+		if requestBody != nil {
+			l.content("/components/requestBodies/"+key+"/content", requestBody.Content)
+		}
+	}
+	for key, response := range doc.Components.Responses {
+		if response != nil {
+			l.response("/components/responses/"+key, response)
+		}
+	}
+	for path, pathItem := range doc.Paths {
+		l.pathItem("/paths/"+path, pathItem)
+	}
+	for name, pathItem := range doc.Webhooks {
+		l.pathItem("/webhooks/"+name, pathItem)
+	}
+	return l.res
+}
+
+type schemaLocator struct {
+	res map[string]*Schema
+	// schemas being located, to stop on recursive schemas
+	visiting map[*Schema]struct{}
+}
+
+func (l *schemaLocator) pathItem(location string, pathItem *PathItem) {
+	if pathItem == nil {
+		return
+	}
+	l.parameters(location+"/parameters", pathItem.Parameters)
+	for method, operation := range pathItem.Operations() {
+		l.operation(location+"/"+method, operation)
+	}
+}
+
+func (l *schemaLocator) operation(location string, operation *Operation) {
+	l.parameters(location+"/parameters", operation.Parameters)
+	if operation.RequestBody != nil {
+		l.content(location+"/requestBody/content", operation.RequestBody.Content)
+	}
+	for code, response := range operation.Responses {
+		if response != nil {
+			l.response(location+"/responses/"+code, response)
+		}
+	}
+	for name, callback := range operation.Callbacks {
+		if callback == nil || callback.Value == nil {
+			continue
+		}
+		for expression, pathItem := range *callback.Value {
+			l.pathItem(location+"/callbacks/"+name+"/"+expression, pathItem)
+		}
+	}
+}
+
+func (l *schemaLocator) parameters(location string, parameters Parameters) {
+	for i, parameter := range parameters {
+		if parameter == nil {
+			continue
+		}
+		key := parameter.In + ":" + parameter.Name
+		if parameter.Ref != "" {
+			key = strconv.Itoa(i)
+		}
+		l.parameter(location+"/"+key, parameter)
+	}
+}
+
+func (l *schemaLocator) parameter(location string, parameter *Parameter) {
+	l.schema(location+"/schema", parameter.Schema)
+	l.content(location+"/content", parameter.Content)
+}
+
+func (l *schemaLocator) header(location string, header *Header) {
+	l.schema(location+"/schema", header.Schema)
+	l.content(location+"/content", header.Content)
+}
+
+func (l *schemaLocator) response(location string, response *Response) {
+	l.content(location+"/content", response.Content)
+	for name, header := range response.Headers {
+		if header != nil && header.Value != nil {
+			l.header(location+"/headers/"+name, header.Value)
+		}
+	}
+}
+
+func (l *schemaLocator) content(location string, content Content) {
+	for mime, mediaType := range content {
+		if mediaType != nil {
+			l.schema(location+"/"+mime+"/schema", mediaType.Schema)
+		}
+	}
+}
+
+func (l *schemaLocator) schema(location string, schema *Schema) {
+	if schema == nil {
+		return
+	}
+	if _, ok := l.visiting[schema]; ok {
+		return
+	}
+	l.visiting[schema] = struct{}{}
+	defer delete(l.visiting, schema)
+
+	l.res[location] = schema
+	l.schema(location+"/not", schema.Not)
+	l.schema(location+"/items", schema.Items)
+	l.schema(location+"/additionalProperties", schema.AdditionalProperties)
+	for i, item := range schema.OneOf {
+		l.schema(location+"/oneOf/"+strconv.Itoa(i), item)
+	}
+	for i, item := range schema.AnyOf {
+		l.schema(location+"/anyOf/"+strconv.Itoa(i), item)
+	}
+	for i, item := range schema.AllOf {
+		l.schema(location+"/allOf/"+strconv.Itoa(i), item)
+	}
+	for name, property := range schema.Properties {
+		l.schema(location+"/properties/"+name, property)
+	}
+}
@@ -0,0 +1,218 @@
+package spec
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/chenwei67/eapi/utils"
+)
+
+const componentSchemasPrefix = "#/components/schemas/"
+
+// WalkSchema calls visit for schema and all the schemas nested in it (including extended type info).
+// References are not followed.
+func WalkSchema(schema *Schema, visit func(schema *Schema)) {
+	walkSchema(schema, visit, make(map[*Schema]struct{}))
+}
+
+func walkSchema(schema *Schema, visit func(schema *Schema), visited map[*Schema]struct{}) {
+	if schema == nil {
+		return
+	}
+	if _, ok := visited[schema]; ok {
+		return
+	}
+	visited[schema] = struct{}{}
+
+	visit(schema)
+
+	walkSchema(schema.Not, visit, visited)
+	walkSchema(schema.Items, visit, visited)
+	walkSchema(schema.AdditionalProperties, visit, visited)
+	for _, item := range schema.OneOf {
+		walkSchema(item, visit, visited)
+	}
+	for _, item := range schema.AnyOf {
+		walkSchema(item, visit, visited)
+	}
+	for _, item := range schema.AllOf {
+		walkSchema(item, visit, visited)
+	}
+	keys := make([]string, 0, len(schema.Properties))
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		walkSchema(schema.Properties[key], visit, visited)
+	}
+
+	ext := schema.ExtendedTypeInfo
+	if ext == nil {
+		return
+	}
+	walkSchema(ext.Items, visit, visited)
+	walkSchema(ext.MapKey, visit, visited)
+	walkSchema(ext.MapValue, visit, visited)
+	if ext.SpecificType != nil {
+		walkSchema(ext.SpecificType.Type, visit, visited)
+		for _, arg := range ext.SpecificType.Args {
+			walkSchema(arg, visit, visited)
+		}
+	}
+}
+
+// ComponentSchemaKey returns key of the component schema which is referenced by ref.
+// Returns empty string if ref does not point to "#/components/schemas/".
+func ComponentSchemaKey(ref string) string {
+	if !strings.HasPrefix(ref, componentSchemasPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, componentSchemasPrefix)
+}
+
+// RewriteSchemaRefs replaces references to component schemas nested in schema (including discriminator mapping).
+// rename returns the new key of the component schema and whether it should be replaced.
+func RewriteSchemaRefs(schema *Schema, rename func(key string) (string, bool)) {
+	WalkSchema(schema, func(schema *Schema) {
+		if key, ok := rename(ComponentSchemaKey(schema.Ref)); ok {
+			schema.Ref = componentSchemasPrefix + key
+		}
+		if schema.Discriminator == nil {
+			return
+		}
+		for value, ref := range schema.Discriminator.Mapping {
+			if key, ok := rename(ComponentSchemaKey(ref)); ok {
+				schema.Discriminator.Mapping[value] = componentSchemasPrefix + key
+			}
+		}
+	})
+}
+
+// RequestSchemas returns root schemas of parameters and request body of operation
+func (operation *Operation) RequestSchemas() []*Schema {
+	var res []*Schema
+	for _, param := range operation.Parameters {
+		if param == nil {
+			continue
+		}
+		res = append(res, param.Schema)
+		res = append(res, param.Content.schemas()...)
+	}
+	if operation.RequestBody != nil {
+		res = append(res, operation.RequestBody.Content.schemas()...)
+	}
+	return res
+}
+
+// ResponseSchemas returns root schemas of responses (including headers) of operation
+func (operation *Operation) ResponseSchemas() []*Schema {
+	var res []*Schema
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		response := operation.Responses[code]
+		if response == nil {
+			continue
+		}
+		res = append(res, response.Content.schemas()...)
+		for _, name := range utils.SortedKeys(response.Headers) {
+			header := response.Headers[name]
+			if header == nil || header.Value == nil {
+				continue
+			}
+			res = append(res, header.Value.Schema)
+			res = append(res, header.Value.Content.schemas()...)
+		}
+	}
+	return res
+}
+
+// ReferencedSchemas returns keys of the component schemas which are (indirectly) referenced by roots
+func (doc *T) ReferencedSchemas(roots ...*Schema) map[string]struct{} {
+	res := make(map[string]struct{})
+	var queue []string
+	add := func(key string) {
+		if key == "" {
+			return
+		}
+		if _, ok := res[key]; ok {
+			return
+		}
+		res[key] = struct{}{}
+		queue = append(queue, key)
+	}
+	collect := func(schema *Schema) {
+		add(ComponentSchemaKey(schema.Ref))
+		if schema.Discriminator != nil {
+			for _, value := range utils.SortedKeys(schema.Discriminator.Mapping) {
+				add(ComponentSchemaKey(schema.Discriminator.Mapping[value]))
+			}
+		}
+	}
+	for _, root := range roots {
+		WalkSchema(root, collect)
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		WalkSchema(doc.Components.Schemas[key], collect)
+	}
+	return res
+}
+
+func (content Content) schemas() []*Schema {
+	var res []*Schema
+	for _, mime := range utils.SortedKeys(content) {
+		mediaType := content[mime]
+		if mediaType != nil && mediaType.Schema != nil {
+			res = append(res, mediaType.Schema)
+		}
+	}
+	return res
+}
+
+// Operations calls fn for every operation of doc in order of path and method
+func (doc *T) Operations(fn func(path, method string, operation *Operation)) {
+	for _, path := range utils.SortedKeys(doc.Paths) {
+		pathItem := doc.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		operations := pathItem.Operations()
+		for _, method := range utils.SortedKeys(operations) {
+			fn(path, method, operations[method])
+		}
+	}
+}
+
+// OutboundOperations calls fn for every operation which is initiated by the server, that is, operations of
+// callbacks (key is the url expression) and webhooks (key is the name of webhook)
+func (doc *T) OutboundOperations(fn func(key, method string, operation *Operation)) {
+	visit := func(key string, pathItem *PathItem) {
+		if pathItem == nil {
+			return
+		}
+		operations := pathItem.Operations()
+		for _, method := range utils.SortedKeys(operations) {
+			fn(key, method, operations[method])
+		}
+	}
+	doc.Operations(func(path, method string, operation *Operation) {
+		for _, name := range utils.SortedKeys(operation.Callbacks) {
+			callback := operation.Callbacks[name]
+			if callback == nil || callback.Value == nil {
+				continue
+			}
+			for _, expression := range utils.SortedKeys(*callback.Value) {
+				visit(expression, (*callback.Value)[expression])
+			}
+		}
+	})
+	for _, name := range utils.SortedKeys(doc.Webhooks) {
+		visit(name, doc.Webhooks[name])
+	}
+}
@@ -0,0 +1,70 @@
+package spec
+
+import "sort"
+
+// parameterLocationOrder is the order of parameters in different locations
+var parameterLocationOrder = map[string]int{
+	ParameterInPath:   0,
+	ParameterInQuery:  1,
+	ParameterInHeader: 2,
+	ParameterInCookie: 3,
+}
+
+// Sort sorts parameters by location (path, query, header, cookie) and then name. References are kept in place
+// relatively to each other, after the inline parameters
+func (parameters Parameters) Sort() {
+	order := func(parameter *Parameter) int {
+		if parameter == nil || parameter.Ref != "" {
+			return len(parameterLocationOrder) + 1
+		}
+		if res, ok := parameterLocationOrder[parameter.In]; ok {
+			return res
+		}
+		return len(parameterLocationOrder)
+	}
+	sort.SliceStable(parameters, func(i, j int) bool {
+		x, y := parameters[i], parameters[j]
+		if order(x) != order(y) {
+			return order(x) < order(y)
+		}
+		if x == nil || y == nil || x.Ref != "" {
+			return false
+		}
+		return x.Name < y.Name
+	})
+}
+
+// SortParameters sorts parameters of all the path items and operations (including callbacks and webhooks), so that
+// the output does not depend on the order in which they are found.
+// Paths, component schemas and responses need no sorting since map keys are sorted (by bytes) on encoding. For
+// responses, this puts numeric status codes (all of three digits) in numeric order, each range after the codes of
+// its class ("201" < "2XX" < "400") and "default" last.
+func (doc *T) SortParameters() {
+	for _, pathItem := range doc.Paths {
+		if pathItem != nil {
+			pathItem.Parameters.Sort()
+		}
+	}
+	for _, pathItem := range doc.Webhooks {
+		if pathItem != nil {
+			pathItem.Parameters.Sort()
+		}
+	}
+	doc.Operations(func(path, method string, operation *Operation) {
+		for _, callback := range operation.Callbacks {
+			if callback == nil || callback.Value == nil {
+				continue
+			}
+			for _, pathItem := range *callback.Value {
+				if pathItem != nil {
+					pathItem.Parameters.Sort()
+				}
+			}
+		}
+	})
+	sortOperation := func(key, method string, operation *Operation) {
+		operation.Parameters.Sort()
+	}
+	doc.Operations(sortOperation)
+	doc.OutboundOperations(sortOperation)
+}
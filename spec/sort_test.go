@@ -0,0 +1,48 @@
+package spec
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameters_Sort(t *testing.T) {
+	parameters := Parameters{
+		NewHeaderParameter("X-Token"),
+		NewQueryParameter("size"),
+		&Parameter{Ref: "#/components/parameters/Trace"},
+		NewCookieParameter("session"),
+		NewPathParameter("id"),
+		NewQueryParameter("page"),
+	}
+	parameters.Sort()
+
+	var names []string
+	for _, parameter := range parameters {
+		names = append(names, parameter.In+":"+parameter.Name+parameter.Ref)
+	}
+	require.Equal(t, []string{
+		"path:id", "query:page", "query:size", "header:X-Token", "cookie:session", ":#/components/parameters/Trace",
+	}, names)
+}
+
+func TestResponses_EncodingOrder(t *testing.T) {
+	responses := make(Responses)
+	for _, code := range []string{"default", "4XX", "200", "1XX", "404", "2XX", "201", "500"} {
+		responses[code] = NewResponse().WithDescription(code)
+	}
+	data, err := json.Marshal(responses)
+	require.NoError(t, err)
+
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return strings.Index(string(data), `"`+codes[i]+`":`) < strings.Index(string(data), `"`+codes[j]+`":`)
+	})
+	require.Equal(t, []string{"1XX", "200", "201", "2XX", "404", "4XX", "500", "default"}, codes)
+}
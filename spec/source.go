@@ -0,0 +1,22 @@
+package spec
+
+const (
+	// ExtensionGoSource is the location of Go code declaring the operation (handler function) or schema (type)
+	ExtensionGoSource = "x-go-source"
+	// ExtensionGoType is the full name of Go type of the schema. e.g. "github.com/org/repo/model.User"
+	ExtensionGoType = "x-go-type"
+)
+
+// SourceLocation is the value of ExtensionGoSource
+type SourceLocation struct {
+	// Slash separated path relative to the module root. Files of dependencies are prefixed with "module@version/"
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Name of the handler function. e.g. "handler.(*UserHandler).Create". Empty for schemas
+	Func string `json:"func,omitempty"`
+}
+
+// isSourceExtension reports whether extension name describes Go code, which does not affect the document structure
+func isSourceExtension(name string) bool {
+	return name == ExtensionGoSource || name == ExtensionGoType
+}
@@ -0,0 +1,417 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chenwei67/eapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// PathGrouper returns name of the file which the path item should be written into
+type PathGrouper func(path string, pathItem map[string]interface{}) string
+
+// GroupPathsByTag groups path items by the first tag of their operations
+func GroupPathsByTag(_ string, pathItem map[string]interface{}) string {
+	for _, method := range utils.SortedKeys(pathItem) {
+		operation, ok := pathItem[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if tags, ok := operation["tags"].([]interface{}); ok && len(tags) > 0 {
+			if tag, ok := tags[0].(string); ok && tag != "" {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// SplitDocument splits the JSON representation of doc into multiple files which are referenced by relative
+// external references:
+//   - path items are moved into "paths/<group>.<ext>", grouped by group (default to "default")
+//   - component schemas are moved into "components/schemas/<Name>.<ext>"
+//
+// rootFile is the file name of the root document, and the other files are placed in the directory of it.
+// Returns file name => content.
+func SplitDocument(doc interface{}, rootFile string, group PathGrouper) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]interface{}
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := path.Ext(rootFile)
+	dir := path.Dir(rootFile) + "/"
+	if dir == "./" {
+		dir = ""
+	}
+	files := map[string]interface{}{rootFile: root}
+	usedFiles := make(map[string]struct{})
+
+	// component schemas
+	schemaFiles := make(map[string]string)
+	components, _ := root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, name := range utils.SortedKeys(schemas) {
+		schemaFiles[name] = uniqueFileName(dir+"components/schemas/", name, ext, usedFiles)
+	}
+	resolve := func(from string) func(ref string) string {
+		return func(ref string) string {
+			if name := ComponentSchemaKey(ref); name != "" {
+				if file, ok := schemaFiles[name]; ok {
+					return relativeRef(from, file)
+				}
+			}
+			if strings.HasPrefix(ref, "#") && from != rootFile {
+				return relativeRef(from, rootFile) + ref
+			}
+			return ref
+		}
+	}
+	for _, name := range utils.SortedKeys(schemas) {
+		file := schemaFiles[name]
+		rewriteJSONRefs(schemas[name], resolve(file))
+		files[file] = schemas[name]
+		schemas[name] = map[string]interface{}{"$ref": relativeRef(rootFile, file)}
+	}
+
+	// path items
+	paths, _ := root["paths"].(map[string]interface{})
+	pathFiles := make(map[string]string)
+	for _, key := range utils.SortedKeys(paths) {
+		pathItem, ok := paths[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := group(key, pathItem)
+		if name == "" {
+			name = "default"
+		}
+		file, ok := pathFiles[name]
+		if !ok {
+			file = uniqueFileName(dir+"paths/", name, ext, usedFiles)
+			pathFiles[name] = file
+			files[file] = make(map[string]interface{})
+		}
+		rewriteJSONRefs(pathItem, resolve(file))
+		files[file].(map[string]interface{})[key] = pathItem
+		paths[key] = map[string]interface{}{"$ref": relativeRef(rootFile, file) + "#/" + escapeJSONPointer(key)}
+	}
+
+	rewriteJSONRefs(root, resolve(rootFile))
+	return files, nil
+}
+
+// BundleFile loads the document which is split into multiple files (e.g. by SplitDocument) and returns the
+// bundled document without external references. Referenced files are moved into "components.schemas" (named by
+// DefaultRefNameResolver unless they are referenced by the root document's components.schemas), and references
+// to parts of files are inlined.
+//
+// The package has no loader resolving external references, and InternalizeRefs only moves references whose values
+// are already resolved, so the files are resolved here. The loader of kin-openapi can not be used either: it
+// overflows the stack on references between files which refer to each other (e.g. schemas of recursive types).
+func BundleFile(rootFile string) (map[string]interface{}, error) {
+	rootFile, err := filepath.Abs(rootFile)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundler{
+		root:    rootFile,
+		files:   make(map[string]interface{}),
+		names:   make(map[string]string),
+		schemas: make(map[string]interface{}),
+	}
+	value, err := b.load(rootFile)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid document %s", rootFile)
+	}
+
+	components, _ := root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, name := range utils.SortedKeys(schemas) {
+		schema, _ := schemas[name].(map[string]interface{})
+		if ref, ok := schema["$ref"].(string); ok && !strings.HasPrefix(ref, "#") && !strings.Contains(ref, "#") {
+			b.names[b.abs(rootFile, ref)] = name
+		}
+	}
+	for _, name := range utils.SortedKeys(schemas) {
+		schema, _ := schemas[name].(map[string]interface{})
+		if ref, ok := schema["$ref"].(string); ok && b.names[b.abs(rootFile, ref)] == name {
+			_, err = b.component(rootFile, ref)
+		} else {
+			b.schemas[name], err = b.resolve(rootFile, schemas[name])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := b.resolve(rootFile, root)
+	if err != nil {
+		return nil, err
+	}
+	bundled := res.(map[string]interface{})
+	if len(b.schemas) > 0 {
+		components, ok := bundled["components"].(map[string]interface{})
+		if !ok {
+			components = make(map[string]interface{})
+			bundled["components"] = components
+		}
+		components["schemas"] = b.schemas
+	}
+	return bundled, nil
+}
+
+type bundler struct {
+	root string
+	// absolute file path => parsed content
+	files map[string]interface{}
+	// absolute file path => component name
+	names   map[string]string
+	schemas map[string]interface{}
+}
+
+func (b *bundler) abs(from, ref string) string {
+	ref, _ = url.PathUnescape(ref)
+	return filepath.Clean(filepath.Join(filepath.Dir(from), filepath.FromSlash(ref)))
+}
+
+func (b *bundler) load(file string) (interface{}, error) {
+	if value, ok := b.files[file]; ok {
+		return value, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	// JSON is a subset of YAML
+	err = yaml.Unmarshal(data, &value)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	b.files[file] = value
+	return value, nil
+}
+
+// resolve returns copy of value (in file) with external references replaced
+func (b *bundler) resolve(file string, value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			return b.resolveRef(file, ref, value)
+		}
+		res := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			if key == "example" || key == "examples" {
+				res[key] = item
+				continue
+			}
+			var err error
+			res[key], err = b.resolve(file, item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if discriminator, ok := res["discriminator"].(map[string]interface{}); ok {
+			if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok {
+				for key, item := range mapping {
+					ref, ok := item.(string)
+					if !ok || strings.HasPrefix(ref, "#") {
+						continue
+					}
+					res, err := b.resolveRef(file, ref, map[string]interface{}{"$ref": ref})
+					if err != nil {
+						return nil, err
+					}
+					mapping[key] = res.(map[string]interface{})["$ref"]
+				}
+			}
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, item := range value {
+			var err error
+			res[i], err = b.resolve(file, item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	return value, nil
+}
+
+func (b *bundler) resolveRef(file, ref string, value map[string]interface{}) (interface{}, error) {
+	target, fragment, _ := strings.Cut(ref, "#")
+	if target == "" {
+		if file == b.root {
+			return value, nil
+		}
+		// reference to part of current file
+		return b.inline(file, fragment)
+	}
+	targetFile := b.abs(file, target)
+	if targetFile == b.root {
+		return map[string]interface{}{"$ref": "#" + fragment}, nil
+	}
+	if fragment != "" && fragment != "/" {
+		return b.inline(targetFile, fragment)
+	}
+
+	// reference to the whole file is moved into components
+	newRef, err := b.component(file, target)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]interface{}, len(value))
+	for key, item := range value {
+		res[key] = item
+	}
+	res["$ref"] = newRef
+	return res, nil
+}
+
+// component moves the referenced file into component schemas. Returns the internal reference
+func (b *bundler) component(file, ref string) (string, error) {
+	targetFile := b.abs(file, ref)
+	name, ok := b.names[targetFile]
+	if !ok {
+		name = DefaultRefNameResolver(ref)
+		for seq := 2; ; seq++ {
+			if _, ok := b.schemas[name]; !ok {
+				break
+			}
+			name = DefaultRefNameResolver(ref) + fmt.Sprint(seq)
+		}
+		b.names[targetFile] = name
+	}
+	newRef := componentSchemasPrefix + name
+	if _, ok := b.schemas[name]; ok {
+		return newRef, nil
+	}
+	b.schemas[name] = nil // placeholder for recursive references
+
+	value, err := b.load(targetFile)
+	if err != nil {
+		return "", err
+	}
+	b.schemas[name], err = b.resolve(targetFile, value)
+	if err != nil {
+		return "", err
+	}
+	return newRef, nil
+}
+
+// inline returns the resolved value pointed by fragment (JSON pointer) in file
+func (b *bundler) inline(file, fragment string) (interface{}, error) {
+	value, err := b.load(file)
+	if err != nil {
+		return nil, err
+	}
+	fragment, _ = url.PathUnescape(fragment)
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		var ok bool
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value, ok = node[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			ok = err == nil && index >= 0 && index < len(node)
+			if ok {
+				value = node[index]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %s#%s", file, fragment)
+		}
+	}
+	return b.resolve(file, value)
+}
+
+// rewriteJSONRefs replaces every reference (including discriminator mappings) in value with the return value of fn
+func rewriteJSONRefs(value interface{}, fn func(ref string) string) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			switch key {
+			case "example", "examples":
+				// literal values
+			case "$ref":
+				if ref, ok := item.(string); ok {
+					value[key] = fn(ref)
+				}
+			case "discriminator":
+				discriminator, _ := item.(map[string]interface{})
+				mapping, _ := discriminator["mapping"].(map[string]interface{})
+				for name, ref := range mapping {
+					if ref, ok := ref.(string); ok {
+						mapping[name] = fn(ref)
+					}
+				}
+			default:
+				rewriteJSONRefs(item, fn)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			rewriteJSONRefs(item, fn)
+		}
+	}
+}
+
+// relativeRef returns the path of file "to" relative to the directory of file "from"
+func relativeRef(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
+
+func escapeJSONPointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return url.PathEscape(token)
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// uniqueFileName returns "<dir><name><ext>" with unsafe characters of name replaced. A sequence is appended to
+// name if the file name (case-insensitive) has been used
+func uniqueFileName(dir, name, ext string, used map[string]struct{}) string {
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(name, "_"), "._")
+	if name == "" {
+		name = "_"
+	}
+	file := dir + name + ext
+	for seq := 2; ; seq++ {
+		if _, ok := used[strings.ToLower(file)]; !ok {
+			break
+		}
+		file = dir + name + "_" + strconv.Itoa(seq) + ext
+	}
+	used[strings.ToLower(file)] = struct{}{}
+	return file
+}
@@ -0,0 +1,84 @@
+package spec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDocument(t *testing.T) {
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Components: Components{
+			Schemas: Schemas{
+				"Pet": NewObjectSchema().
+					WithProperty("name", NewStringSchema()).
+					WithPropertyRef("owner", RefComponentSchemas("model.User")),
+				"model.User": NewObjectSchema().WithPropertyRef("pets", NewArraySchema(RefComponentSchemas("Pet"))),
+			},
+		},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Get: &Operation{
+					Tags:      []string{"pet"},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("Pet"))},
+				},
+			},
+			"/users/{id}": &PathItem{
+				Get: &Operation{
+					Tags:      []string{"user"},
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchemaRef(RefComponentSchemas("model.User"))},
+				},
+			},
+			"/ping": &PathItem{
+				Get: &Operation{Responses: Responses{"200": NewResponse().WithDescription("OK")}},
+			},
+		},
+	}
+
+	files, err := SplitDocument(doc, "openapi.json", GroupPathsByTag)
+	require.NoError(t, err)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{
+		"openapi.json",
+		"components/schemas/Pet.json",
+		"components/schemas/model.User.json",
+		"paths/pet.json",
+		"paths/user.json",
+		"paths/default.json",
+	}, names)
+
+	root := files["openapi.json"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"$ref": "paths/user.json#/~1users~1%7Bid%7D"}, root["paths"].(map[string]interface{})["/users/{id}"])
+	require.Equal(t, map[string]interface{}{"$ref": "components/schemas/Pet.json"}, root["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Pet"])
+	pet := files["components/schemas/Pet.json"].(map[string]interface{})
+	require.Equal(t, "model.User.json", pet["properties"].(map[string]interface{})["owner"].(map[string]interface{})["$ref"])
+	pets := files["paths/pet.json"].(map[string]interface{})["/pets"].(map[string]interface{})
+	data, err := json.Marshal(pets)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"$ref":"../components/schemas/Pet.json"`)
+
+	// bundle the files back into one document
+	dir := t.TempDir()
+	for name, content := range files {
+		data, err := json.Marshal(content)
+		require.NoError(t, err)
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, data, 0644))
+	}
+	bundled, err := BundleFile(filepath.Join(dir, "openapi.json"))
+	require.NoError(t, err)
+	expected, err := json.Marshal(doc)
+	require.NoError(t, err)
+	actual, err := json.Marshal(bundled)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
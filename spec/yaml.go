@@ -0,0 +1,45 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML returns the YAML encoding of v. v is encoded as JSON first (so custom MarshalJSON methods of
+// schemas and references take effect), then converted to YAML with the same key order.
+func ToYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is a subset of YAML. Decode it into node tree to keep the key order
+	var node yaml.Node
+	err = yaml.Unmarshal(data, &node)
+	if err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&node)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle uses block style for collections and quotes strings only when necessary
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, item := range node.Content {
+		resetStyle(item)
+	}
+}
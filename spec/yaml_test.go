@@ -0,0 +1,54 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToYAML(t *testing.T) {
+	schema := NewObjectSchema().
+		WithProperty("name", NewStringSchema()).
+		WithPropertyRef("user", RefComponentSchemas("User"))
+	schema.Extensions = map[string]interface{}{"x-order": 1}
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Example", Version: "1.0"},
+		Paths: Paths{
+			"/users/{id}": &PathItem{
+				Get: &Operation{
+					Summary:   "Get user",
+					Responses: Responses{"200": NewResponse().WithDescription("OK").WithJSONSchema(schema)},
+				},
+			},
+		},
+	}
+
+	data, err := ToYAML(doc)
+	require.NoError(t, err)
+	require.Equal(t, `components: {}
+info:
+  title: Example
+  version: "1.0"
+openapi: 3.0.3
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                ext:
+                  type: object
+                properties:
+                  name:
+                    type: string
+                  user:
+                    $ref: '#/components/schemas/User'
+                type: object
+                x-order: 1
+          description: OK
+      summary: Get user
+`, string(data))
+}
@@ -0,0 +1,117 @@
+package eapi
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SplitByTag     = "tag"
+	SplitByPackage = "package"
+)
+
+type SplitConfig struct {
+	// Group paths into files by: tag | package. Default to tag
+	By string
+}
+
+func (c *SplitConfig) validate() error {
+	switch c.By {
+	case "":
+		c.By = SplitByTag
+	case SplitByTag, SplitByPackage:
+	default:
+		return fmt.Errorf("invalid split.by %q. available: %s, %s", c.By, SplitByTag, SplitByPackage)
+	}
+	return nil
+}
+
+// grouper returns the function which decides the file of each path item
+func (c *SplitConfig) grouper(a *Analyzer) spec.PathGrouper {
+	if c.By != SplitByPackage {
+		return spec.GroupPathsByTag
+	}
+	// package name of the handlers. key: path
+	packages := make(map[string]string)
+	for _, route := range a.routes {
+		if route.Spec == nil || route.Spec.pkg == "" {
+			continue
+		}
+		if _, ok := packages[route.FullPath]; !ok {
+			packages[route.FullPath] = path.Base(route.Spec.pkg)
+		}
+	}
+	return func(path string, _ map[string]interface{}) string {
+		return packages[path]
+	}
+}
+
+// writeSplitDoc writes content into root file and the files referenced by it (paths/<group> and
+// components/schemas/<Name>) in configured format
+func (e *Entrypoint) writeSplitDoc(name string, content interface{}, group spec.PathGrouper) error {
+	name, exts := e.outputFiles(name)
+	for _, ext := range exts {
+		files, err := spec.SplitDocument(content, name+ext, group)
+		if err != nil {
+			return fmt.Errorf("split documentation err: %s", err.Error())
+		}
+		for fileName, fileContent := range files {
+			docContent, err := encodeDoc(ext, fileContent)
+			if err != nil {
+				return err
+			}
+			file := filepath.Join(e.cfg.Output, filepath.FromSlash(fileName))
+			err = os.MkdirAll(filepath.Dir(file), os.ModePerm)
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(file, docContent, fs.ModePerm)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bundleCommand bundles the documentation split into multiple files into a single file
+func bundleCommand() *cli.Command {
+	var output string
+	return &cli.Command{
+		Name:      "bundle",
+		Usage:     "bundle documentation split into multiple files into a single file",
+		ArgsUsage: "<root file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "output file. Format is decided by extension (.json, .yaml or .yml). Print JSON to stdout if not set",
+				Destination: &output,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("root file of the documentation is required")
+			}
+			doc, err := spec.BundleFile(c.Args().First())
+			if err != nil {
+				return err
+			}
+			content, err := encodeDoc(filepath.Ext(output), doc)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				_, err = fmt.Println(string(content))
+				return err
+			}
+			return os.WriteFile(output, content, fs.ModePerm)
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package eapi
+
+import (
+	"sort"
+
+	"github.com/chenwei67/eapi/annotation"
+	"github.com/chenwei67/eapi/spec"
+	"github.com/chenwei67/eapi/utils"
+)
+
+type TagConfig struct {
+	Name         string
+	Description  string
+	ExternalDocs *spec.ExternalDocs `yaml:"externalDocs"`
+	// Tags with explicit order come first (in ascending order), followed by the other tags in declared order
+	Order *int
+}
+
+// declareTags records tags described by @tagDescription annotations. The first description of a tag wins.
+func declareTags(doc *spec.T, comment *Comment) {
+	if comment == nil {
+		return
+	}
+	for _, annot := range comment.Annotations {
+		desc, ok := annot.(*annotation.TagDescriptionAnnotation)
+		if !ok {
+			continue
+		}
+		tag := doc.Tags.Get(desc.Tag)
+		if tag == nil {
+			tag = &spec.Tag{Name: desc.Tag}
+			doc.Tags = append(doc.Tags, tag)
+		}
+		if tag.Description == "" {
+			tag.Description = desc.Description
+		}
+	}
+}
+
+// applyTags builds top-level tags of doc from config, @tagDescription annotations and tags used by operations.
+// Order: tags with explicit order > tags in config > other tags in alphabetical order.
+func (c OpenAPIConfig) applyTags(doc *spec.T) {
+	type entry struct {
+		tag   *spec.Tag
+		order *int
+		index int
+	}
+	var entries []*entry
+	var declared = make(map[string]*entry)
+	for i, item := range c.Tags {
+		if item == nil || item.Name == "" {
+			continue
+		}
+		tag := &spec.Tag{Name: item.Name}
+		if existing := doc.Tags.Get(item.Name); existing != nil {
+			// tags may be shared by documents filtered from the same document
+			*tag = *existing
+		}
+		if item.Description != "" {
+			tag.Description = item.Description
+		}
+		if item.ExternalDocs != nil {
+			tag.ExternalDocs = item.ExternalDocs
+		}
+		if e, ok := declared[item.Name]; ok {
+			e.order = item.Order
+			continue
+		}
+		declared[item.Name] = &entry{tag: tag, order: item.Order, index: i}
+		entries = append(entries, declared[item.Name])
+	}
+
+	var others []*spec.Tag
+	for _, tag := range doc.Tags {
+		if _, ok := declared[tag.Name]; !ok {
+			declared[tag.Name] = &entry{tag: tag}
+			others = append(others, tag)
+		}
+	}
+
+	doc.Operations(func(path, method string, operation *spec.Operation) {
+		for _, name := range operation.Tags {
+			if _, ok := declared[name]; !ok {
+				declared[name] = &entry{}
+				others = append(others, &spec.Tag{Name: name})
+			}
+		}
+	})
+
+	sort.SliceStable(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	for _, tag := range others {
+		entries = append(entries, &entry{tag: tag, index: len(c.Tags) + len(entries)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.order != nil && b.order != nil {
+			return *a.order < *b.order
+		}
+		if a.order != nil || b.order != nil {
+			return a.order != nil
+		}
+		return a.index < b.index
+	})
+
+	doc.Tags = nil
+	for _, e := range entries {
+		doc.Tags = append(doc.Tags, e.tag)
+	}
+}
+
+// checkTags warns about tags which are used by operations of doc but not declared, and tags which are declared
+// (by config or @tagDescription) but not used. It must be called before applyTags, which adds the used tags to doc.
+func checkTags(doc *spec.T, tags []*TagConfig) {
+	declared := make(map[string]struct{})
+	for _, item := range tags {
+		if item != nil && item.Name != "" {
+			declared[item.Name] = struct{}{}
+		}
+	}
+	for _, tag := range doc.Tags {
+		declared[tag.Name] = struct{}{}
+	}
+	// only warn when tags are declared explicitly
+	if len(declared) == 0 {
+		return
+	}
+
+	used := make(map[string]struct{})
+	var undeclared []string
+	doc.Operations(func(path, method string, operation *spec.Operation) {
+		for _, name := range operation.Tags {
+			if _, ok := used[name]; ok {
+				continue
+			}
+			used[name] = struct{}{}
+			if _, ok := declared[name]; !ok {
+				undeclared = append(undeclared, name)
+			}
+		}
+	})
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		LogWarn("tag %q is used by operations but not declared in 'openapi.tags' or by @tagDescription", name)
+	}
+	for _, name := range utils.SortedKeys(declared) {
+		if _, ok := used[name]; !ok {
+			LogWarn("tag %q is declared but not used by any operation", name)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package eapi
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureWarnings returns warnings logged by fn
+func captureWarnings(t *testing.T, fn func()) string {
+	var buf bytes.Buffer
+	logger := GetGlobalLogger()
+	logger.SetErrorOutput(&buf)
+	logger.SetColorized(false)
+	t.Cleanup(func() {
+		logger.SetErrorOutput(os.Stderr)
+		logger.SetColorized(true)
+	})
+	fn()
+	return buf.String()
+}
+
+func newTaggedDoc(tags ...string) *spec.T {
+	doc := &spec.T{Info: &spec.Info{}, Paths: make(spec.Paths)}
+	for _, tag := range tags {
+		doc.AddOperation("/"+tag, "GET", &spec.Operation{Tags: []string{tag}})
+	}
+	return doc
+}
+
+func TestCheckTags(t *testing.T) {
+	doc := newTaggedDoc("goods", "orders")
+	warnings := captureWarnings(t, func() {
+		checkTags(doc, []*TagConfig{{Name: "goods"}, {Name: "users"}})
+	})
+	assert.Equal(t, "[WARN] tag \"orders\" is used by operations but not declared in 'openapi.tags' or by @tagDescription\n"+
+		"[WARN] tag \"users\" is declared but not used by any operation\n", warnings)
+
+	warnings = captureWarnings(t, func() {
+		checkTags(newTaggedDoc("goods"), nil)
+	})
+	assert.Empty(t, warnings, "no warnings when no tag is declared")
+}
+
+func TestOpenAPIConfig_applyToDoc_Documents(t *testing.T) {
+	cfg := &Config{
+		OpenAPI: OpenAPIConfig{Tags: []*TagConfig{{Name: "goods", Description: "Goods"}}},
+		Documents: []*DocumentConfig{
+			{OutputFile: "a", Tags: []string{"goods"}},
+			{OutputFile: "b", Tags: []string{"orders"}, OpenAPI: OpenAPIConfig{Tags: []*TagConfig{{Name: "orders"}}}},
+		},
+	}
+	doc := newTaggedDoc("goods", "orders")
+	warnings := captureWarnings(t, func() {
+		checkTags(doc, cfg.documentTags())
+		for _, item := range cfg.Documents {
+			document, err := item.build(doc, nil, "")
+			assert.NoError(t, err)
+			cfg.OpenAPI.override(item.OpenAPI).applyToDoc(document)
+		}
+	})
+	assert.Empty(t, warnings)
+}
@@ -15,13 +15,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var plugins = map[string]analyzer.Plugin{}
-
-func init() {
-	var pluginList = []analyzer.Plugin{gin.NewPlugin(), echo.NewPlugin()}
-	for _, plugin := range pluginList {
-		plugins[plugin.Name()] = plugin
-	}
+// plugins 保存了各个插件的构造函数. 插件在 Mount 时会保存配置, 因此每个测试用例都需要新的插件实例
+var plugins = map[string]func() analyzer.Plugin{
+	"gin":  func() analyzer.Plugin { return gin.NewPlugin() },
+	"echo": func() analyzer.Plugin { return echo.NewPlugin() },
 }
 
 func TestAnalyzer(t *testing.T) {
@@ -50,6 +47,12 @@ func TestAnalyzer(t *testing.T) {
 				pkgPath: "./testdata/gin",
 			},
 		},
+		{
+			name: "annotations",
+			args: args{
+				pkgPath: "./testdata/annotations",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -61,10 +64,10 @@ func TestAnalyzer(t *testing.T) {
 			err = k.Unmarshal("", &config)
 			assert.NoError(t, err)
 
-			plugin, ok := plugins[config.Plugin]
+			newPlugin, ok := plugins[config.Plugin]
 			assert.Truef(t, ok, "plugin %s not exists", config.Plugin)
 
-			a := analyzer.NewAnalyzer(k).Plugin(plugin).Depends(config.Depends...).Process(tt.args.pkgPath)
+			a := analyzer.NewAnalyzer(k).Plugin(newPlugin()).Depends(config.Depends...).Process(tt.args.pkgPath)
 			expectedDoc, err := os.ReadFile(filepath.Join(tt.args.pkgPath, "docs/openapi.json"))
 			assert.NoError(t, err)
 
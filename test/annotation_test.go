@@ -0,0 +1,44 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	analyzer "github.com/chenwei67/eapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotation_UnresolvedType(t *testing.T) {
+	var buf bytes.Buffer
+	logger := analyzer.GetGlobalLogger()
+	logger.SetErrorOutput(&buf)
+	t.Cleanup(func() { logger.SetErrorOutput(os.Stderr) })
+
+	doc, _ := generateDoc(t, "./testdata/annotations")
+	file, err := filepath.Abs("./testdata/annotations/pkg/order/order.go")
+	require.NoError(t, err)
+	// the error is reported at the type operand of "@response 500 Missing"
+	assert.Contains(t, buf.String(), "[Invalid Annotation]: type 'Missing' not found at "+file+":52:18\n")
+
+	responses := doc.Paths["/orders"].Post.Responses
+	assert.NotContains(t, responses, "500")
+	assert.Contains(t, responses, "201")
+}
+
+func TestSchema_EmbeddedRequired(t *testing.T) {
+	doc, _ := generateDoc(t, "./testdata/annotations")
+	// required fields of the embedded struct are merged in order of declaration
+	assert.Equal(t, []string{"createdBy", "id"}, doc.Components.Schemas["annotations_pkg_order.Order"].Required)
+}
+
+func TestAnnotation_TagsPrecedence(t *testing.T) {
+	doc, _ := generateDoc(t, "./testdata/annotations")
+	// @tags of handler > @tags in file comment > @tags in package comment (doc.go)
+	assert.Equal(t, []string{"User"}, doc.Paths["/admin/users"].Get.Tags)
+	assert.Equal(t, []string{"Admin"}, doc.Paths["/admin/stats"].Get.Tags)
+	assert.Equal(t, []string{"Log"}, doc.Paths["/admin/logs/{id}"].Get.Tags)
+	assert.Equal(t, []string{"Audit"}, doc.Paths["/admin/logs"].Get.Tags)
+}
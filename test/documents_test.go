@@ -0,0 +1,126 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	analyzer "github.com/chenwei67/eapi"
+	"github.com/chenwei67/eapi/plugins/gin"
+	"github.com/chenwei67/eapi/spec"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runEntrypoint runs eapi with the config (in YAML) whose 'dir' is pkgPath and 'output' is a temporary directory.
+// files are written into the output directory before running, and "{{output}}" in the config is replaced with the
+// output directory. Returns the output directory
+func runEntrypoint(t *testing.T, pkgPath string, config string, files map[string]string) string {
+	output, configFile := writeConfig(t, pkgPath, config, files)
+	analyzer.NewEntrypoint(gin.NewPlugin()).Run([]string{"eapi", "--config", configFile})
+	return output
+}
+
+// writeConfig writes the config and files into a temporary output directory for runEntrypoint.
+// Returns the output directory and the config file
+func writeConfig(t *testing.T, pkgPath string, config string, files map[string]string) (string, string) {
+	dir, err := filepath.Abs(pkgPath)
+	require.NoError(t, err)
+	output := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(output, name), []byte(content), 0644))
+	}
+	config = strings.ReplaceAll(config, "{{output}}", output)
+	config = "dir: " + dir + "\noutput: " + output + "\n" + config
+	configFile := filepath.Join(output, "eapi.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(config), 0644))
+	return output, configFile
+}
+
+func readDoc(t *testing.T, file string) *spec.T {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var doc spec.T
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return &doc
+}
+
+func TestEntrypoint_Documents(t *testing.T) {
+	output := runEntrypoint(t, "./testdata/multi_entry", `
+plugin: gin
+openapi:
+  info:
+    title: base
+    version: 1.0.0
+documents:
+  - outputFile: a
+    packages: [./app_a]
+    openapi:
+      info:
+        title: A
+  - outputFile: b
+    packages: [./app_b]
+`, nil)
+
+	assert.NoFileExists(t, filepath.Join(output, "openapi.json"))
+
+	a := readDoc(t, filepath.Join(output, "a.json"))
+	assert.Equal(t, []string{"/app-a/hello"}, lo.Keys(a.Paths))
+	assert.Equal(t, "A", a.Info.Title)
+	assert.Equal(t, "1.0.0", a.Info.Version)
+
+	b := readDoc(t, filepath.Join(output, "b.json"))
+	assert.Equal(t, []string{"/app-b/hello"}, lo.Keys(b.Paths))
+	assert.Equal(t, "base", b.Info.Title)
+	assert.Equal(t, "1.0.0", b.Info.Version)
+}
+
+func TestEntrypoint_DocumentsOverlay(t *testing.T) {
+	output := runEntrypoint(t, "./testdata/multi_entry", `
+plugin: gin
+overlays:
+  - "{{output}}/overlay.yaml"
+openapi:
+  info:
+    title: base
+    version: 1.0.0
+  securitySchemes:
+    token:
+      type: apiKey
+      name: X-Token
+      in: header
+documents:
+  - outputFile: a
+    packages: [./app_a]
+    openapi:
+      info:
+        title: A
+  - outputFile: b
+    packages: [./app_b]
+`, map[string]string{
+		"overlay.yaml": `
+overlay: 1.0.0
+info:
+  title: test
+  version: 1.0.0
+actions:
+  - target: $.info
+    update:
+      title: FromOverlay
+  - target: $.components.securitySchemes.*
+    update:
+      description: From overlay
+`,
+	})
+
+	for _, name := range []string{"a.json", "b.json"} {
+		doc := readDoc(t, filepath.Join(output, name))
+		assert.Equal(t, "FromOverlay", doc.Info.Title, name)
+		assert.Equal(t, "1.0.0", doc.Info.Version, name)
+		require.Contains(t, doc.Components.SecuritySchemes, "token", name)
+		assert.Equal(t, "From overlay", doc.Components.SecuritySchemes["token"].Value.Description, name)
+	}
+}
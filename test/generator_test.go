@@ -0,0 +1,29 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chenwei67/eapi/generators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateTypes returns TypeScript types generated by "ts" generator from the document of the package
+func generateTypes(t *testing.T, pkgPath string) string {
+	a, config := process(t, pkgPath)
+	doc := a.Doc().Specialize()
+	config.OpenAPI.ApplyToDoc(doc)
+	res := generators.Generators["ts"].Print(doc, &generators.PrintOptions{
+		GetConfig: func(key string) interface{} { return nil },
+	})
+	require.Len(t, res, 1)
+	return res[0].Code
+}
+
+func TestGenerator_TS(t *testing.T) {
+	expected, err := os.ReadFile(filepath.Join("./testdata/annotations", "docs/types.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), generateTypes(t, "./testdata/annotations"))
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_AddSourceExtensions(t *testing.T) {
+	a, _ := process(t, "./testdata/gin")
+	doc := a.Doc().Specialize()
+	a.AddSourceExtensions(doc)
+
+	operation := doc.Paths["/api/controller/goods/{guid}"].Delete
+	require.NotNil(t, operation)
+	source := &spec.SourceLocation{File: "pkg/controller/goods.go", Line: 11, Func: "controller.(*GoodsController).Delete"}
+	assert.Equal(t, source, operation.Extensions[spec.ExtensionGoSource])
+
+	schema := doc.Components.Schemas["server_pkg_view.GoodsDownRes"]
+	require.NotNil(t, schema)
+	assert.Equal(t, "server/pkg/view.GoodsDownRes", schema.Extensions[spec.ExtensionGoType])
+	assert.Equal(t, &spec.SourceLocation{File: "pkg/view/shop.go", Line: 74}, schema.Extensions[spec.ExtensionGoSource])
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	analyzer "github.com/chenwei67/eapi"
+	"github.com/chenwei67/eapi/plugins/gin"
+	"github.com/chenwei67/eapi/spec"
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// process analyzes the package with eapi.yaml in it
+func process(t *testing.T, pkgPath string) (*analyzer.Analyzer, *analyzer.Config) {
+	k := koanf.New(".")
+	err := k.Load(file.Provider(filepath.Join(pkgPath, "eapi.yaml")), yaml.Parser())
+	require.NoError(t, err)
+
+	var config analyzer.Config
+	err = k.Unmarshal("", &config)
+	require.NoError(t, err)
+
+	newPlugin, ok := plugins[config.Plugin]
+	require.Truef(t, ok, "plugin %s not exists", config.Plugin)
+
+	return analyzer.NewAnalyzer(k).Plugin(newPlugin()).Depends(config.Depends...).Process(pkgPath), &config
+}
+
+func generateDoc(t *testing.T, pkgPath string) (*spec.T, []byte) {
+	a, config := process(t, pkgPath)
+	doc := a.Doc().Specialize()
+	doc.SortParameters()
+	config.OpenAPI.ApplyToDoc(doc)
+	res, err := json.MarshalIndent(doc, "", "    ")
+	require.NoError(t, err)
+	return doc, res
+}
+
+func TestAnalyzer_StableOutput(t *testing.T) {
+	doc, expected := generateDoc(t, "./testdata/gin")
+	_, actual := generateDoc(t, "./testdata/gin")
+	assert.Equal(t, string(expected), string(actual))
+
+	locations := map[string]int{spec.ParameterInPath: 0, spec.ParameterInQuery: 1, spec.ParameterInHeader: 2, spec.ParameterInCookie: 3}
+	doc.Operations(func(path, method string, operation *spec.Operation) {
+		for i := 1; i < len(operation.Parameters); i++ {
+			x, y := operation.Parameters[i-1], operation.Parameters[i]
+			sorted := locations[x.In] < locations[y.In] || x.In == y.In && x.Name <= y.Name
+			assert.Truef(t, sorted, "parameters of %s %s are not sorted: %s %s, %s %s", method, path, x.In, x.Name, y.In, y.Name)
+		}
+	})
+}
+
+// TestHelperEntrypoint runs eapi with the config file in $EAPI_TEST_CONFIG. It is used to run eapi in a separate process
+func TestHelperEntrypoint(t *testing.T) {
+	configFile := os.Getenv("EAPI_TEST_CONFIG")
+	if configFile == "" {
+		t.Skip("only runs as a helper process")
+	}
+	analyzer.NewEntrypoint(gin.NewPlugin()).Run([]string{"eapi", "--config", configFile})
+}
+
+// runEntrypointProcess is the same as runEntrypoint, but runs eapi in a separate process
+func runEntrypointProcess(t *testing.T, pkgPath string, config string) string {
+	output, configFile := writeConfig(t, pkgPath, config, nil)
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperEntrypoint$")
+	cmd.Env = append(os.Environ(), "EAPI_TEST_CONFIG="+configFile)
+	res, err := cmd.CombinedOutput()
+	require.NoErrorf(t, err, "%s", res)
+	return output
+}
+
+// TestEntrypoint_StableOutput runs all the passes of eapi (naming, merging, pruning and splitting schemas) in two
+// processes, and compares the written documents
+func TestEntrypoint_StableOutput(t *testing.T) {
+	content, err := os.ReadFile("./testdata/gin/eapi.yaml")
+	require.NoError(t, err)
+	// 'dir' and 'output' are set by runEntrypoint
+	lines := lo.Filter(strings.Split(string(content), "\n"), func(line string, _ int) bool {
+		return !strings.HasPrefix(line, "dir:") && !strings.HasPrefix(line, "output:")
+	})
+	config := strings.Join(lines, "\n") + `
+splitSchemasByDirection: true
+pruneSchemas: true
+dedupeSchemas: true
+schemaNaming:
+  strategy: short
+  generic: concat
+`
+
+	expected, err := os.ReadFile(filepath.Join(runEntrypoint(t, "./testdata/gin", config, nil), "openapi.json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(expected), "#/components/schemas/server_pkg_", "schemas are not renamed")
+	actual, err := os.ReadFile(filepath.Join(runEntrypointProcess(t, "./testdata/gin", config), "openapi.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
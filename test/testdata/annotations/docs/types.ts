@@ -0,0 +1,136 @@
+export type AdminLog = {
+  id?: number;
+  message?: string;
+}
+
+export type AdminStat = {
+  users?: number;
+}
+
+/*
+ * @description Event is a sealed interface, whose implementations are found automatically
+ */
+export type EventEvent = EventUserCreated | EventUserDeleted
+
+export type EventImageNotice = {
+  url?: string;
+}
+
+export type EventListRes = {
+  events?: EventEvent[];
+  notice?: EventNotice;
+}
+
+/*
+ * @description Notice is a sealed interface without discriminator
+ */
+export type EventNotice = EventImageNotice | EventTextNotice
+
+export type EventTextNotice = {
+  text?: string;
+}
+
+export type EventUserCreated = {
+  kind?: string;
+  userId?: number;
+}
+
+export type EventUserDeleted = {
+  kind?: string;
+  reason?: string;
+  userId?: number;
+}
+
+export type OrderAudit = {
+  createdBy: string;
+}
+
+export type OrderCreateReq = {
+  items?: string[];
+}
+
+export type OrderErrorResp = {
+  code?: number;
+  message?: string;
+}
+
+export type OrderOrder = {
+  createdBy: string;
+  id: number;
+  items?: string[];
+}
+
+export type OrderPage<T> = {
+  items?: T[];
+  total?: number;
+}
+
+export type OrderPair<A, B> = {
+  first?: A;
+  second?: B;
+}
+
+export type OrderResp<T> = {
+  code?: number;
+  data?: T;
+}
+
+export type OrderSummary = {
+  buyers?: OrderPage<OrderResp<UserUser>>;
+  latest?: OrderPair<OrderOrder, UserUser>;
+}
+
+export type PetCat = {
+  lives?: number;
+  type?: string;
+}
+
+export type PetDog = {
+  breed?: string;
+  type?: string;
+}
+
+export type PetEnvelope = {
+  friend?: PetCat | PetDog;
+  pet?: PetCat | PetDog;
+}
+
+/*
+ * @description Level of the user
+ */
+export type UserLevel = string
+
+export type UserListQuery = {
+  /*
+   * @description search keyword
+   */
+  keyword?: string;
+}
+
+/*
+ * @description Status of the user
+ */
+export enum UserStatus {
+  StatusActive = 1,
+  StatusBanned = 2,
+}
+
+/*
+ * @description User is the account of the shop
+ */
+export type UserUser = {
+  level?: UserLevel;
+  /*
+   * @description name of the user
+   */
+  name?: string;
+  /*
+   * @description Nickname is an alias of string
+   */
+  nickname?: string;
+  /*
+   * @description status of the user
+   * 	defaults to active
+   */
+  status?: UserStatus;
+}
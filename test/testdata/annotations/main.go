@@ -0,0 +1,27 @@
+package main
+
+import (
+	"annotations/pkg/admin"
+	"annotations/pkg/event"
+	"annotations/pkg/order"
+	"annotations/pkg/pet"
+	"annotations/pkg/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	r := gin.New()
+	r.GET("/events", event.List)
+	r.GET("/orders", order.List)
+	r.POST("/orders", order.Create)
+	r.GET("/orders/:id", order.Get)
+	r.GET("/orders/buyers", order.Buyers)
+	r.GET("/orders/latest", order.Latest)
+	r.GET("/orders/stats", order.Stats)
+	r.POST("/pets", pet.Create)
+	r.GET("/users", user.List)
+	admin.Register(r)
+	admin.RegisterAudit(r)
+	_ = r.Run()
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+
+	"annotations/pkg/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Stat struct {
+	Users int `json:"users"`
+}
+
+type Log struct {
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+// ListUsers lists all the users including disabled ones
+// @tags User
+func ListUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, []user.User{})
+}
+
+// Stats reports statistics of the shop
+func Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, Stat{})
+}
+
+// ListLogs lists audit logs
+func ListLogs(c *gin.Context) {
+	c.JSON(http.StatusOK, []Log{})
+}
+
+// GetLog returns an audit log
+// @tags Log
+func GetLog(c *gin.Context) {
+	c.JSON(http.StatusOK, Log{})
+}
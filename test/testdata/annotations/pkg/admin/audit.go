@@ -0,0 +1,9 @@
+// @tags Audit
+package admin
+
+import "github.com/gin-gonic/gin"
+
+func RegisterAudit(r *gin.Engine) {
+	r.GET("/admin/logs", ListLogs)
+	r.GET("/admin/logs/:id", GetLog)
+}
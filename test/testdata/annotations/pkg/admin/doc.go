@@ -0,0 +1,3 @@
+// Package admin registers routes of the admin console
+// @tags Admin
+package admin
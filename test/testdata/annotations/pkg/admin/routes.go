@@ -0,0 +1,9 @@
+// @prefix /admin
+package admin
+
+import "github.com/gin-gonic/gin"
+
+func Register(r *gin.Engine) {
+	r.GET("/users", ListUsers)
+	r.GET("/stats", Stats)
+}
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Event is a sealed interface, whose implementations are found automatically
+// @discriminator kind
+type Event interface {
+	isEvent()
+}
+
+// @discriminatorValue user.created
+type UserCreated struct {
+	Kind   string `json:"kind"`
+	UserId int64  `json:"userId"`
+}
+
+func (UserCreated) isEvent() {}
+
+type UserDeleted struct {
+	Kind   string `json:"kind"`
+	UserId int64  `json:"userId"`
+	Reason string `json:"reason"`
+}
+
+func (*UserDeleted) isEvent() {}
+
+// Notice is a sealed interface without discriminator
+type Notice interface {
+	isNotice()
+}
+
+type TextNotice struct {
+	Text string `json:"text"`
+}
+
+func (TextNotice) isNotice() {}
+
+type ImageNotice struct {
+	Url string `json:"url"`
+}
+
+func (ImageNotice) isNotice() {}
+
+type ListRes struct {
+	Events []Event `json:"events"`
+	Notice Notice  `json:"notice"`
+}
+
+func List(c *gin.Context) {
+	c.JSON(http.StatusOK, ListRes{})
+}
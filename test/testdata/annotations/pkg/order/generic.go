@@ -0,0 +1,39 @@
+package order
+
+import (
+	"net/http"
+
+	"annotations/pkg/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Resp[T any] struct {
+	Code int `json:"code"`
+	Data T   `json:"data"`
+}
+
+type Pair[A any, B any] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+}
+
+type Summary struct {
+	Buyers Page[Resp[user.User]]  `json:"buyers"`
+	Latest Pair[Order, user.User] `json:"latest"`
+}
+
+// Buyers lists buyers of orders
+func Buyers(c *gin.Context) {
+	c.JSON(http.StatusOK, Page[Resp[user.User]]{})
+}
+
+// Latest returns the latest order and its buyer
+func Latest(c *gin.Context) {
+	c.JSON(http.StatusOK, Pair[Order, user.User]{})
+}
+
+// Stats returns the summary of orders
+func Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, Summary{})
+}
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Audit struct {
+	// @required
+	CreatedBy string `json:"createdBy"`
+}
+
+type Order struct {
+	Audit
+	// @required
+	ID    int64    `json:"id"`
+	Items []string `json:"items"`
+}
+
+type CreateReq struct {
+	Items []string `json:"items"`
+}
+
+type ErrorResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// List orders
+// @param page query integer required "page number"
+// @param status query []string "filter by status"
+// @param X-Request-Id header string
+// @response default ErrorResp
+func List(c *gin.Context) {
+	page := pageOf(c)
+	status := c.Query("status")
+	keyword := c.Query("keyword")
+	_, _, _ = page, status, keyword
+	c.JSON(http.StatusOK, Page[Order]{})
+}
+
+// Create an order
+// @request CreateReq
+// @response 201 Order "created"
+// @response 400 ErrorResp "invalid request"
+// @response 500 Missing
+// @callback orderPaid "{$request.body#/callbackUrl}" post Order "order paid"
+// @callback orderPaid "{$request.body#/callbackUrl}" delete
+func Create(c *gin.Context) {
+	var req map[string]interface{}
+	_ = c.ShouldBindJSON(&req)
+	c.JSON(http.StatusOK, req)
+}
+
+// Get an order
+// @param id path integer "order id"
+// @response 200 Order
+func Get(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+}
+
+// pageOf reads page number in the way which can not be analyzed
+func pageOf(c *gin.Context) int {
+	return 1
+}
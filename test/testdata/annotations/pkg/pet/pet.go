@@ -0,0 +1,31 @@
+package pet
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Cat struct {
+	Type  string `json:"type"`
+	Lives int    `json:"lives"`
+}
+
+type Dog struct {
+	Type  string `json:"type"`
+	Breed string `json:"breed"`
+}
+
+type Envelope struct {
+	// @oneOf cat=Cat dog=Dog
+	// @discriminator type
+	Pet interface{} `json:"pet"`
+	// @oneOf Cat Dog
+	Friend interface{} `json:"friend"`
+}
+
+func Create(c *gin.Context) {
+	var req Envelope
+	_ = c.ShouldBindJSON(&req)
+	c.JSON(http.StatusOK, req)
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// User is the account of the shop
+type User struct {
+	Name string `json:"name"` // name of the user
+	// status of the user
+	Status   Status   `json:"status"` // defaults to active
+	Level    Level    `json:"level"`
+	Nickname Nickname `json:"nickname"`
+}
+
+// Status of the user
+type Status int
+
+const (
+	StatusActive Status = 1 // the user is active
+	// the user is banned
+	StatusBanned Status = 2
+)
+
+type (
+	// Level of the user
+	Level string
+	// Nickname is an alias of string
+	Nickname = string
+)
+
+type ListQuery struct {
+	Keyword string `form:"keyword"` // search keyword
+}
+
+func List(c *gin.Context) {
+	var query ListQuery
+	_ = c.ShouldBindQuery(&query)
+	c.JSON(http.StatusOK, []User{})
+}
@@ -37,6 +37,7 @@ type GoodsCreateReq struct {
 
 type SelfRefType struct {
 	Data   string       `json:"data"`
+	// @nullable
 	Parent *SelfRefType `json:"parent"`
 }
 
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntrypoint_Webhooks(t *testing.T) {
+	output := runEntrypoint(t, "./testdata/annotations", `
+plugin: gin
+openapi:
+  openapi: 3.1.0
+  info:
+    title: Annotations
+    version: 1.0.0
+  webhooks:
+    - name: orderCreated
+      type: annotations/pkg/order.Order
+      summary: order created
+    - name: orderCreated
+      type: annotations/pkg/order.Order
+      method: delete
+`, nil)
+
+	doc := readDoc(t, filepath.Join(output, "openapi.json"))
+	require.Contains(t, doc.Webhooks, "orderCreated")
+	webhook := doc.Webhooks["orderCreated"]
+	require.NotNil(t, webhook.Post)
+	assert.Equal(t, "order created", webhook.Post.Summary)
+	assert.Equal(t, "#/components/schemas/annotations_pkg_order.Order", webhook.Post.RequestBody.Content["application/json"].Schema.Ref)
+	assert.NotNil(t, webhook.Delete)
+
+	// callbacks declared by @callback are written into the same document
+	callback := doc.Paths["/orders"].Post.Callbacks["orderPaid"]
+	require.NotNil(t, callback)
+	pathItem := (*callback.Value)["{$request.body#/callbackUrl}"]
+	require.NotNil(t, pathItem)
+	assert.Equal(t, "order paid", pathItem.Post.Summary)
+	assert.NotNil(t, pathItem.Delete)
+}
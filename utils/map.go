@@ -14,3 +14,13 @@ func RangeMapInOrder[K comparable, V any](values map[K]V, sorter func(i, j K) bo
 		visitor(key, values[key])
 	}
 }
+
+// SortedKeys returns keys of the map in ascending order
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,172 @@
+package eapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/chenwei67/eapi/spec"
+	"github.com/samber/lo"
+)
+
+// VariantConfig declares an additional document filtered from each written document. e.g. public document without
+// admin routes. Operations, parameters and fields marked with @internal are removed unless 'internal' is true, and
+// component schemas which are no longer referenced are pruned.
+type VariantConfig struct {
+	// Required. Appended to the file name of the document. e.g. "public" writes "openapi.public.json"
+	Name string
+	// Keep only the operations matched by include. All the operations are kept if not set
+	Include *FilterConfig
+	// Remove the operations matched by exclude
+	Exclude *FilterConfig
+	// Keep operations, parameters and fields marked with @internal
+	Internal bool
+}
+
+// FilterConfig matches the operations which match any of the conditions
+type FilterConfig struct {
+	Tags []string
+	// Glob patterns of paths. "*" matches one path segment and "**" matches any number of segments
+	Paths []string
+	// Regular expressions of operationId
+	OperationIds []string `yaml:"operationIds"`
+	// Extensions of operations. e.g. {"x-audience": "admin"}. Operations with the extension are matched if value is empty
+	Extensions map[string]interface{}
+
+	paths        []*regexp.Regexp
+	operationIds []*regexp.Regexp
+}
+
+func (e *Entrypoint) validateVariants() error {
+	names := make(map[string]struct{})
+	for i, item := range e.cfg.Variants {
+		if item == nil || item.Name == "" {
+			return fmt.Errorf("invalid variants[%d]: 'name' is required", i)
+		}
+		if _, ok := names[item.Name]; ok {
+			return fmt.Errorf("invalid variants[%d]: duplicated name %q", i, item.Name)
+		}
+		names[item.Name] = struct{}{}
+		for _, filter := range []*FilterConfig{item.Include, item.Exclude} {
+			err := filter.compile()
+			if err != nil {
+				return fmt.Errorf("invalid variants[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *FilterConfig) compile() error {
+	if c == nil {
+		return nil
+	}
+	for _, pattern := range c.Paths {
+		c.paths = append(c.paths, globToRegexp(pattern))
+	}
+	for _, pattern := range c.OperationIds {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid operationIds %q: %w", pattern, err)
+		}
+		c.operationIds = append(c.operationIds, re)
+	}
+	return nil
+}
+
+// globToRegexp converts path glob pattern to regular expression. "*" matches one path segment and "**" matches any
+// number of segments
+func globToRegexp(pattern string) *regexp.Regexp {
+	var res strings.Builder
+	res.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			res.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			res.WriteString("[^/]*")
+		default:
+			res.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	res.WriteString("$")
+	return regexp.MustCompile(res.String())
+}
+
+func (c *FilterConfig) match(path string, operation *spec.Operation) bool {
+	if lo.Some(operation.Tags, c.Tags) {
+		return true
+	}
+	for _, re := range c.paths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	for _, re := range c.operationIds {
+		if operation.OperationID != "" && re.MatchString(operation.OperationID) {
+			return true
+		}
+	}
+	for name, value := range c.Extensions {
+		actual, ok := operation.Extensions[name]
+		if ok && (value == nil || jsonEqual(actual, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual reports whether a and b have the same JSON encoding. e.g. int and float64 from different decoders
+func jsonEqual(a, b interface{}) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(x) == string(y)
+}
+
+func (c *VariantConfig) build(doc *spec.T) *spec.T {
+	res := doc.FilterOperations(func(path, method string, operation *spec.Operation) bool {
+		if c.Include != nil && !c.Include.match(path, operation) {
+			return false
+		}
+		return c.Exclude == nil || !c.Exclude.match(path, operation)
+	})
+	if !c.Internal {
+		res = res.RemoveInternal()
+	}
+	return res
+}
+
+// fileName returns file name of the variant of document name. Split documents are written into sub directory
+// named by the variant, as they share the same directory structure
+func (c *VariantConfig) fileName(name string, split bool) string {
+	if split {
+		return path.Join(path.Dir(name), c.Name, path.Base(name))
+	}
+	return name + "." + c.Name
+}
+
+// writeDocWithVariants writes doc and its variants
+func (e *Entrypoint) writeDocWithVariants(doc *spec.T, a *Analyzer, name, swaggerName string) error {
+	err := e.writeDoc(doc, a, name, swaggerName)
+	if err != nil {
+		return err
+	}
+	for _, variant := range e.cfg.Variants {
+		document := variant.build(doc)
+		LogInfo("variant %s of %s: %d paths", variant.Name, name, len(document.Paths))
+		err = e.writeDoc(document, a, variant.fileName(name, e.cfg.Split != nil), variant.fileName(swaggerName, false))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
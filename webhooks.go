@@ -0,0 +1,75 @@
+package eapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chenwei67/eapi/annotation"
+	"github.com/chenwei67/eapi/spec"
+	"github.com/samber/lo"
+)
+
+type WebhookConfig struct {
+	Name string
+	// HTTP method of the webhook request. Default to POST
+	Method string
+	// Full name of the payload type. e.g. "github.com/org/repo/event.OrderCreated"
+	Type        string
+	ContentType string `yaml:"contentType"` // Default to application/json
+	Summary     string
+	Description string
+}
+
+// buildWebhooks adds webhooks declared in config to doc of the analyzer. Webhooks are only available since OpenAPI 3.1
+func (c OpenAPIConfig) buildWebhooks(a *Analyzer) error {
+	if len(c.Webhooks) == 0 {
+		return nil
+	}
+	if !(&spec.T{OpenAPI: c.OpenAPI}).IsOpenAPI31() {
+		LogWarn("webhooks are only supported in OpenAPI 3.1, but the version is %q. webhooks are ignored", c.OpenAPI)
+		return nil
+	}
+
+	doc := a.Doc()
+	for _, item := range c.Webhooks {
+		if item == nil || item.Name == "" || item.Type == "" {
+			return fmt.Errorf("invalid openapi.webhooks: both 'name' and 'type' are required")
+		}
+		method := strings.ToUpper(item.Method)
+		if method == "" {
+			method = http.MethodPost
+		}
+		if !lo.Contains(annotation.OperationMethods, method) {
+			return fmt.Errorf("invalid method %q of webhook %s", item.Method, item.Name)
+		}
+		def, ok := a.definitions[item.Type].(*TypeDefinition)
+		if !ok {
+			return fmt.Errorf("type %s of webhook %s not found", item.Type, item.Name)
+		}
+		contentType := item.ContentType
+		if contentType == "" {
+			contentType = MimeTypeJson
+		}
+
+		ctx := a.context().WithPackage(def.pkg).WithFile(def.file)
+		schema := ctx.GetSchemaByExpr(def.Spec.Name, contentType)
+		operation := spec.NewOperation()
+		operation.OperationID = item.Name
+		operation.Summary = item.Summary
+		operation.Description = item.Description
+		operation.RequestBody = spec.NewRequestBody().WithSchemaRef(schema, []string{contentType})
+		operation.Responses = spec.Responses{"200": spec.NewResponse().WithDescription(http.StatusText(http.StatusOK))}
+
+		if doc.Webhooks == nil {
+			doc.Webhooks = make(map[string]*spec.PathItem)
+		}
+		pathItem := doc.Webhooks[item.Name]
+		if pathItem == nil {
+			pathItem = &spec.PathItem{}
+			doc.Webhooks[item.Name] = pathItem
+		}
+		pathItem.SetOperation(method, operation)
+	}
+	return nil
+}